@@ -0,0 +1,129 @@
+package feedback
+
+import (
+	"testing"
+
+	"control/linalg"
+)
+
+// twelveStates returns a setpoint, measurement and gain for a 12-state system.
+func twelveStates() (setpoint, measurement, gain Values) {
+	setpoint = make(Values, 12)
+	measurement = make(Values, 12)
+	gain = make(Values, 12)
+	for i := range 12 {
+		setpoint[i] = float64(i)
+		measurement[i] = float64(i) * 0.9
+		gain[i] = float64(i%4) * 0.25
+	}
+	return setpoint, measurement, gain
+}
+
+func TestCalculateAllocations(t *testing.T) {
+	setpoint, measurement, gain := twelveStates()
+
+	t.Run("FullStateFeedback", func(t *testing.T) {
+		controller := New(gain,
+			WithOutputLimits(-1, 1),
+			WithVoltageCompensation(12, func() float64 { return 11.5 }),
+		)
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _ = controller.Calculate(setpoint, measurement)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected 0 allocations, got %f", allocs)
+		}
+	})
+
+	t.Run("ScheduledFeedback", func(t *testing.T) {
+		controller, _ := NewScheduled(Values{0, 1, 2}, []Values{gain, gain, gain}, WithOutputLimits(-1, 1))
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _ = controller.Calculate(0.5, setpoint, measurement)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected 0 allocations, got %f", allocs)
+		}
+	})
+
+	t.Run("MultiInputFeedback", func(t *testing.T) {
+		controller, _ := NewMultiInput(Matrix{gain, gain, gain}, WithOutputLimits(-1, 1))
+		output := make(Values, 3)
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = controller.CalculateInto(output, setpoint, measurement)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected 0 allocations, got %f", allocs)
+		}
+
+		expected, _ := controller.Calculate(setpoint, measurement)
+		for i := range expected {
+			if output[i] != expected[i] {
+				t.Errorf("Output[%d] = %f, expected %f", i, output[i], expected[i])
+			}
+		}
+	})
+
+	t.Run("IntegralFeedback", func(t *testing.T) {
+		selection := linalg.New(2, 12)
+		selection[0][0], selection[1][6] = 1, 1
+		controller, _ := NewIntegralFeedback(Matrix{gain, gain}, Matrix{{1, 0}, {0, 1}}, selection, 0.001, WithOutputLimits(-1, 1))
+		output := make(Values, 2)
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = controller.CalculateInto(output, setpoint, measurement)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected 0 allocations, got %f", allocs)
+		}
+	})
+
+	t.Run("Regulator", func(t *testing.T) {
+		model, _ := NewDiscreteStateSpace(linalg.Identity(12), linalg.New(12, 1), linalg.New(3, 12), nil, 0.001)
+		observer, _ := NewObserver(model, linalg.New(12, 3))
+		regulator, _ := NewRegulator(New(gain), observer)
+		y := Values{1, 2, 3}
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _ = regulator.Calculate(setpoint, y)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected 0 allocations, got %f", allocs)
+		}
+	})
+}
+
+func BenchmarkFullStateFeedbackCalculate12States(b *testing.B) {
+	setpoint, measurement, gain := twelveStates()
+	controller := New(gain, WithOutputLimits(-12, 12))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		controller.Calculate(setpoint, measurement)
+	}
+}
+
+func BenchmarkMultiInputFeedbackCalculateInto(b *testing.B) {
+	setpoint, measurement, gain := twelveStates()
+	controller, _ := NewMultiInput(Matrix{gain, gain})
+	output := make(Values, 2)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		controller.CalculateInto(output, setpoint, measurement)
+	}
+}
+
+func BenchmarkRegulatorCalculate(b *testing.B) {
+	_, _, gain := twelveStates()
+	model, _ := NewDiscreteStateSpace(linalg.Identity(12), linalg.New(12, 1), linalg.New(3, 12), nil, 0.001)
+	observer, _ := NewObserver(model, linalg.New(12, 3))
+	regulator, _ := NewRegulator(New(gain), observer)
+	setpoint := make(Values, 12)
+	y := Values{1, 2, 3}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		regulator.Calculate(setpoint, y)
+	}
+}
@@ -1,7 +1,27 @@
 package feedback
 
-import "errors"
+import (
+	"errors"
+
+	"control/linalg"
+)
 
 var (
 	ErrSlicessMustBeSameLength = errors.New("vectors must be of same length")
+	ErrDimensionMismatch       = linalg.ErrDimensionMismatch
+	ErrSingularMatrix          = linalg.ErrSingularMatrix
+	ErrNoSolution              = errors.New("riccati equation has no stabilizing solution")
+	ErrMultipleInputs          = errors.New("system has more than one input")
+	ErrUncontrollable          = errors.New("system is not controllable")
+	ErrUnobservable            = errors.New("system is not observable")
+	ErrInvalidPoles            = errors.New("poles must match the number of states and come in conjugate pairs")
+	ErrPoleMultiplicity        = errors.New("pole multiplicity exceeds the number of inputs")
+	ErrNoConvergence           = linalg.ErrNoConvergence
+	ErrInvalidSampleTime       = errors.New("sample time must be positive")
+	ErrNotDiscrete             = errors.New("model is not discrete-time")
+	ErrNotContinuous           = errors.New("model is not continuous-time")
+	ErrInvalidMethod           = errors.New("unknown discretization method")
+	ErrInvalidLimits           = errors.New("minimum limit must not exceed maximum limit")
+	ErrInvalidSchedule         = errors.New("gain schedule needs at least two distinct finite operating points")
+	ErrInvalidOperatingPoint   = errors.New("operating point must be a number")
 )
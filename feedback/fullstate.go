@@ -6,35 +6,88 @@ type Values []float64
 // (position, velocity, etc) of our system in parallel. This type of controller works especially
 // well with motion profiles.
 type FullStateFeedback struct {
-	gain Values
+	gain   Values
+	output outputStage
 }
 
-// New creates a new FullStateFeedback controller with the specified gain values.
-func New(gain Values) *FullStateFeedback {
-	return &FullStateFeedback{
-		gain: gain,
+// New creates a new FullStateFeedback controller with the specified gain values and options.
+// It is NewFullState for options known to be valid, and panics with the error NewFullState
+// would return if they are not.
+func New(gain Values, opts ...Option) *FullStateFeedback {
+	fsf, err := NewFullState(gain, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return fsf
+}
+
+// NewFullState creates a new FullStateFeedback controller with the specified gain values and
+// options.
+//
+// Returns ErrDimensionMismatch if input limits do not have exactly one value and
+// ErrInvalidLimits if a minimum exceeds its maximum.
+func NewFullState(gain Values, opts ...Option) (*FullStateFeedback, error) {
+	output := newOutputStage(1, opts)
+	if output.err != nil {
+		return nil, output.err
 	}
+	return &FullStateFeedback{
+		gain:   gain,
+		output: output,
+	}, nil
 }
 
-// Calculate computes the control output based on the full state feedback
+// Calculate computes the control output based on the full state feedback, applying voltage
+// compensation and the output limits.
 func (fsf *FullStateFeedback) Calculate(setpoint, measurement Values) (float64, error) {
-	errorVec, err := minus(setpoint, measurement)
+	scale := fsf.output.begin()
+	u, err := errorProduct(fsf.gain, setpoint, measurement)
 	if err != nil {
 		return 0, err
 	}
-	return product(errorVec, fsf.gain)
+	return fsf.output.limit(0, u*scale), nil
+}
+
+// SetOutputLimits sets the minimum and maximum output values.
+//
+// Returns ErrInvalidLimits and keeps the previous limits if the minimum exceeds the maximum.
+func (fsf *FullStateFeedback) SetOutputLimits(min, max float64) error {
+	return fsf.output.setLimits(Values{min}, Values{max})
+}
+
+// GetOutputLimits returns the current output limits
+func (fsf *FullStateFeedback) GetOutputLimits() (min, max float64) {
+	return fsf.output.min[0], fsf.output.max[0]
 }
 
-// minus is a helper function that subtracts two vectors element-wise
-func minus(a, b Values) (Values, error) {
+// IsSaturated reports whether the output of the last call to Calculate was limited.
+func (fsf *FullStateFeedback) IsSaturated() bool {
+	return fsf.output.saturated
+}
+
+// minusInto is a helper function that subtracts two vectors element-wise into dst, which must
+// have the same length
+func minusInto(dst, a, b Values) error {
 	if len(a) != len(b) {
-		return nil, ErrSlicessMustBeSameLength
+		return ErrSlicessMustBeSameLength
 	}
-	result := make(Values, len(a))
 	for i := range len(a) {
-		result[i] = a[i] - b[i]
+		dst[i] = a[i] - b[i]
 	}
-	return result, nil
+	return nil
+}
+
+// errorProduct is a helper function that computes the dot product of the gain with the error
+// between setpoint and measurement without allocating
+func errorProduct(gain, setpoint, measurement Values) (float64, error) {
+	if len(setpoint) != len(measurement) || len(gain) != len(setpoint) {
+		return 0, ErrSlicessMustBeSameLength
+	}
+	sum := 0.0
+	for i := range len(gain) {
+		sum += gain[i] * (setpoint[i] - measurement[i])
+	}
+	return sum, nil
 }
 
 // product is a helper function that computes the dot product of two vectors
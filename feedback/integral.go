@@ -0,0 +1,250 @@
+package feedback
+
+import (
+	"math"
+	"slices"
+
+	"control/linalg"
+)
+
+// IntegralFeedback is full state feedback with integral action on selected outputs, so that
+// constant disturbances such as gravity on a lift leave no steady-state error. The control
+// output is
+//
+//	u = Kx (r - x) + Ki z, z[k+1] = z[k] + dt Cr (r[k] - x[k])
+//
+// where Cr selects the integrated outputs from the state. The output stage accepts the same
+// options as MultiInputFeedback, and an integrator is held while integrating would drive a
+// saturated output further into its limit.
+type IntegralFeedback struct {
+	stateGain    Matrix
+	integralGain Matrix
+	selection    Matrix
+	sampleTime   float64
+	integral     Values
+	output       outputStage
+
+	// Scratch buffers that keep Calculate free of allocations
+	errorVec  Values
+	direction []float64
+}
+
+// NewIntegralFeedback creates an IntegralFeedback controller that runs every sampleTime
+// seconds. For m inputs, n states and q integrated outputs the state gain is m by n, the
+// integral gain m by q and the selection q by n. The matrices are copied.
+//
+// Returns ErrDimensionMismatch if the matrix sizes are inconsistent, ErrInvalidSampleTime if
+// the sample time is not positive, or any error from the options as for NewMultiInput.
+func NewIntegralFeedback(stateGain, integralGain, selection Matrix, sampleTime float64, opts ...Option) (*IntegralFeedback, error) {
+	inputs, states := stateGain.Dims()
+	if inputs == 0 || states <= 0 {
+		return nil, ErrDimensionMismatch
+	}
+	integrators, cols := selection.Dims()
+	if integrators == 0 || cols != states {
+		return nil, ErrDimensionMismatch
+	}
+	if rows, cols := integralGain.Dims(); rows != inputs || cols != integrators {
+		return nil, ErrDimensionMismatch
+	}
+	if sampleTime <= 0 || math.IsInf(sampleTime, 0) || math.IsNaN(sampleTime) {
+		return nil, ErrInvalidSampleTime
+	}
+
+	output := newOutputStage(inputs, opts)
+	if output.err != nil {
+		return nil, output.err
+	}
+	return &IntegralFeedback{
+		stateGain:    stateGain.Clone(),
+		integralGain: integralGain.Clone(),
+		selection:    selection.Clone(),
+		sampleTime:   sampleTime,
+		integral:     make(Values, integrators),
+		output:       output,
+		errorVec:     make(Values, states),
+		direction:    make([]float64, inputs),
+	}, nil
+}
+
+// NewLQI creates an IntegralFeedback controller for a discrete-time model using the gains
+// computed by LQI and the given options, running at the model's sample time.
+//
+// Returns ErrNotDiscrete for a continuous-time model, or any error from LQI or
+// NewIntegralFeedback.
+func NewLQI(model *StateSpace, outputs []int, q, r Matrix, opts ...Option) (*IntegralFeedback, error) {
+	if !model.IsDiscrete() {
+		return nil, ErrNotDiscrete
+	}
+	stateGain, integralGain, err := LQI(model, outputs, q, r)
+	if err != nil {
+		return nil, err
+	}
+	selection, _ := outputSelection(model, outputs)
+	return NewIntegralFeedback(stateGain, integralGain, selection, model.GetSampleTime(), opts...)
+}
+
+// LQI computes linear-quadratic gains with integral action on the given outputs of the model,
+// which are indices into the rows of C. The state is augmented with the integrals z of the
+// output errors, and LQR or DiscreteLQR is solved for the augmented system, so Q weights the n
+// states followed by the integrals and must be (n+q) by (n+q). Feedthrough from D to the
+// integrated outputs is ignored.
+//
+// Returns ErrDimensionMismatch if an output index is out of range or Q and R have the wrong
+// size, and ErrNoSolution if the augmented system is not stabilizable, for example when the
+// plant has a zero at the origin.
+func LQI(model *StateSpace, outputs []int, q, r Matrix) (stateGain, integralGain Matrix, err error) {
+	selection, err := outputSelection(model, outputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	n, m, integrators := model.States(), model.Inputs(), len(outputs)
+
+	// Augmented dynamics [x; z] with dz/dt = -Cr x in continuous time and
+	// z[k+1] = z[k] - dt Cr x[k] in discrete time
+	a := linalg.New(n+integrators, n+integrators)
+	b := linalg.New(n+integrators, m)
+	for i := range n {
+		copy(a[i], model.a[i])
+		copy(b[i], model.b[i])
+	}
+	scale := 1.0
+	if model.IsDiscrete() {
+		scale = model.GetSampleTime()
+	}
+	for i := range integrators {
+		for j := range n {
+			a[n+i][j] = -scale * selection[i][j]
+		}
+		if model.IsDiscrete() {
+			a[n+i][n+i] = 1
+		}
+	}
+
+	var k Matrix
+	if model.IsDiscrete() {
+		k, err = DiscreteLQR(a, b, q, r)
+	} else {
+		k, err = LQR(a, b, q, r)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// u = -Kx x - Kz z, and the controller adds Ki z
+	stateGain = linalg.New(m, n)
+	integralGain = linalg.New(m, integrators)
+	for i := range m {
+		copy(stateGain[i], k[i][:n])
+		for j := range integrators {
+			integralGain[i][j] = -k[i][n+j]
+		}
+	}
+	return stateGain, integralGain, nil
+}
+
+// outputSelection returns the rows of C for the given output indices.
+func outputSelection(model *StateSpace, outputs []int) (Matrix, error) {
+	if len(outputs) == 0 {
+		return nil, ErrDimensionMismatch
+	}
+	selection := make(Matrix, len(outputs))
+	for i, index := range outputs {
+		if index < 0 || index >= model.Outputs() {
+			return nil, ErrDimensionMismatch
+		}
+		selection[i] = slices.Clone(model.c[index])
+	}
+	return selection, nil
+}
+
+// Calculate computes the control output vector and advances the integrators by one sample.
+// Integrators are held while integrating would push a saturated output further into its limit.
+//
+// Returns ErrSlicessMustBeSameLength if the setpoint and measurement have different lengths and
+// ErrDimensionMismatch if their length does not match the number of states.
+func (ifb *IntegralFeedback) Calculate(setpoint, measurement Values) (Values, error) {
+	output := make(Values, len(ifb.stateGain))
+	if err := ifb.CalculateInto(output, setpoint, measurement); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// CalculateInto is like Calculate, but writes the control output vector into a caller-owned
+// buffer with one value per input and does not allocate.
+//
+// Returns ErrDimensionMismatch if the output buffer has the wrong length, or any error from
+// Calculate.
+func (ifb *IntegralFeedback) CalculateInto(output, setpoint, measurement Values) error {
+	if len(setpoint) != len(measurement) {
+		return ErrSlicessMustBeSameLength
+	}
+	if len(setpoint) != len(ifb.errorVec) || len(output) != len(ifb.stateGain) {
+		return ErrDimensionMismatch
+	}
+	_ = minusInto(ifb.errorVec, setpoint, measurement)
+
+	scale := ifb.output.begin()
+	for i := range output {
+		u, _ := product(ifb.stateGain[i], ifb.errorVec)
+		integral, _ := product(ifb.integralGain[i], ifb.integral)
+		raw := (u + integral) * scale
+		output[i] = ifb.output.limit(i, raw)
+		ifb.direction[i] = 0
+		if output[i] != raw {
+			ifb.direction[i] = math.Copysign(1, raw-output[i])
+		}
+	}
+
+	for j, row := range ifb.selection {
+		step, _ := product(row, ifb.errorVec)
+		step *= ifb.sampleTime
+		windup := false
+		for i := range output {
+			if ifb.direction[i]*ifb.integralGain[i][j]*step > 0 {
+				windup = true
+				break
+			}
+		}
+		if !windup {
+			ifb.integral[j] += step
+		}
+	}
+	return nil
+}
+
+// SetInputLimits sets the minimum and maximum output of each input. Infinite values disable a
+// limit.
+//
+// Returns ErrDimensionMismatch if the limits do not have one value per input and
+// ErrInvalidLimits if a minimum exceeds its maximum.
+func (ifb *IntegralFeedback) SetInputLimits(min, max Values) error {
+	return ifb.output.setLimits(min, max)
+}
+
+// GetInputLimits returns copies of the per-input limits.
+func (ifb *IntegralFeedback) GetInputLimits() (min, max Values) {
+	return ifb.output.limits()
+}
+
+// IsSaturated reports whether any output was limited by the last call to Calculate.
+func (ifb *IntegralFeedback) IsSaturated() bool {
+	return ifb.output.saturated
+}
+
+// GetGains returns copies of the state and integral gains.
+func (ifb *IntegralFeedback) GetGains() (stateGain, integralGain Matrix) {
+	return ifb.stateGain.Clone(), ifb.integralGain.Clone()
+}
+
+// GetIntegral returns a copy of the integrated output errors.
+func (ifb *IntegralFeedback) GetIntegral() Values {
+	return slices.Clone(ifb.integral)
+}
+
+// Reset clears the integrators and the saturation flag.
+func (ifb *IntegralFeedback) Reset() {
+	clear(ifb.integral)
+	ifb.output.saturated = false
+}
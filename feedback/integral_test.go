@@ -0,0 +1,179 @@
+package feedback
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"control/linalg"
+)
+
+// lift returns a discretized lift whose input is the commanded acceleration.
+func lift(t *testing.T, dt float64) *StateSpace {
+	t.Helper()
+	plant, _ := NewStateSpace(Matrix{{0, 1}, {0, 0}}, Matrix{{0}, {1}}, nil, nil)
+	model, err := plant.Discretize(dt, ZeroOrderHold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+// simulateLift runs the lift against gravity for the given number of samples. The command is
+// clamped to the actuator limit before it reaches the plant, and the largest position reached
+// is returned with the final state.
+func simulateLift(model *StateSpace, control func(x Values) float64, limit float64, samples int) (final Values, peak float64) {
+	const gravity = 9.81
+	x := Values{0, 0}
+	for range samples {
+		u := max(-limit, min(limit, control(x)))
+		x, _ = model.Step(x, Values{u - gravity})
+		peak = max(peak, x[0])
+	}
+	return x, peak
+}
+
+func TestIntegralFeedback(t *testing.T) {
+	dt := 0.01
+	model := lift(t, dt)
+	setpoint := Values{1, 0}
+
+	t.Run("Removes the steady-state error", func(t *testing.T) {
+		a, b, _, _ := model.Matrices()
+		lqr, _ := NewDiscreteLQR(a, b, linalg.Diagonal(10, 1), Matrix{{0.1}})
+		final, _ := simulateLift(model, func(x Values) float64 {
+			u, _ := lqr.Calculate(setpoint, x)
+			return u
+		}, math.Inf(1), 2000)
+		if math.Abs(final[0]-1) < 0.1 {
+			t.Errorf("Expected a steady-state error without integral action, got %f", final[0])
+		}
+
+		lqi, err := NewLQI(model, []int{0}, linalg.Diagonal(10, 1, 50), Matrix{{0.1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		final, _ = simulateLift(model, func(x Values) float64 {
+			u, _ := lqi.Calculate(setpoint, x)
+			return u[0]
+		}, math.Inf(1), 2000)
+		if math.Abs(final[0]-1) > 1e-3 || math.Abs(final[1]) > 1e-3 {
+			t.Errorf("Expected to settle at [1, 0], got %v", final)
+		}
+
+		// The integrator has learned the gravity compensation
+		_, integralGain := lqi.GetGains()
+		if compensation := integralGain[0][0] * lqi.GetIntegral()[0]; math.Abs(compensation-9.81) > 1e-2 {
+			t.Errorf("Expected the integral to supply 9.81, got %f", compensation)
+		}
+
+		lqi.Reset()
+		if lqi.GetIntegral()[0] != 0 {
+			t.Error("Expected the integral to be cleared")
+		}
+	})
+
+	t.Run("Anti-windup", func(t *testing.T) {
+		const limit = 15.0
+		stateGain, integralGain, err := LQI(model, []int{0}, linalg.Diagonal(10, 1, 50), Matrix{{0.1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Without limits the integrator winds up while the actuator saturates
+		unlimited, _ := NewIntegralFeedback(stateGain, integralGain, Matrix{{1, 0}}, dt)
+		step := Values{5, 0}
+		_, windupPeak := simulateLift(model, func(x Values) float64 {
+			u, _ := unlimited.Calculate(step, x)
+			return u[0]
+		}, limit, 3000)
+
+		limited, _ := NewIntegralFeedback(stateGain, integralGain, Matrix{{1, 0}}, dt)
+		if err := limited.SetInputLimits(Values{-limit}, Values{limit}); err != nil {
+			t.Fatal(err)
+		}
+		saturated := false
+		final, peak := simulateLift(model, func(x Values) float64 {
+			u, _ := limited.Calculate(step, x)
+			saturated = saturated || limited.IsSaturated()
+			return u[0]
+		}, limit, 3000)
+
+		if !saturated {
+			t.Error("Expected the output to saturate")
+		}
+		if math.Abs(final[0]-5) > 1e-3 {
+			t.Errorf("Expected to settle at 5, got %f", final[0])
+		}
+		if peak-5 >= windupPeak-5 {
+			t.Errorf("Expected less overshoot with anti-windup: %f vs %f", peak-5, windupPeak-5)
+		}
+		if min, max := limited.GetInputLimits(); min[0] != -limit || max[0] != limit {
+			t.Errorf("Unexpected limits %v, %v", min, max)
+		}
+	})
+
+	t.Run("Continuous design", func(t *testing.T) {
+		plant, _ := NewStateSpace(Matrix{{0, 1}, {0, 0}}, Matrix{{0}, {1}}, nil, nil)
+		stateGain, integralGain, err := LQI(plant, []int{0}, linalg.Diagonal(10, 1, 50), Matrix{{0.1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The closed loop of the augmented system is stable
+		closed, _ := NewStateSpace(Matrix{
+			{0, 1, 0},
+			{-stateGain[0][0], -stateGain[0][1], integralGain[0][0]},
+			{-1, 0, 0},
+		}, Matrix{{0}, {0}, {0}}, nil, nil)
+		if stable, _ := closed.IsStable(); !stable {
+			t.Error("Expected a stable augmented closed loop")
+		}
+
+		if _, err := NewLQI(plant, []int{0}, linalg.Diagonal(10, 1, 50), Matrix{{0.1}}); !errors.Is(err, ErrNotDiscrete) {
+			t.Errorf("Expected ErrNotDiscrete, got %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, _, err := LQI(model, []int{2}, linalg.Diagonal(1, 1, 1), Matrix{{1}}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+		if _, _, err := LQI(model, nil, linalg.Diagonal(1, 1), Matrix{{1}}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+		if _, _, err := LQI(model, []int{0}, linalg.Diagonal(1, 1), Matrix{{1}}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+
+		// Velocity of a damped oscillator has a zero at the origin, so its integral cannot be held
+		oscillator, _ := NewStateSpace(Matrix{{0, 1}, {-1, -1}}, Matrix{{0}, {1}}, Matrix{{0, 1}}, nil)
+		if _, _, err := LQI(oscillator, []int{0}, linalg.Diagonal(1, 1, 1), Matrix{{1}}); !errors.Is(err, ErrNoSolution) {
+			t.Errorf("Expected ErrNoSolution, got %v", err)
+		}
+
+		if _, err := NewIntegralFeedback(Matrix{{1, 1}}, Matrix{{1}}, Matrix{{1, 0}}, 0); !errors.Is(err, ErrInvalidSampleTime) {
+			t.Errorf("Expected ErrInvalidSampleTime, got %v", err)
+		}
+		if _, err := NewIntegralFeedback(Matrix{{1, 1}}, Matrix{{1, 1}}, Matrix{{1, 0}}, dt); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+		if _, err := NewIntegralFeedback(Matrix{{1, 1}}, Matrix{{1}}, Matrix{{1}}, dt); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+
+		controller, _ := NewIntegralFeedback(Matrix{{1, 1}}, Matrix{{1}}, Matrix{{1, 0}}, dt)
+		if err := controller.SetInputLimits(Values{1}, Values{-1}); !errors.Is(err, ErrInvalidLimits) {
+			t.Errorf("Expected ErrInvalidLimits, got %v", err)
+		}
+		if err := controller.SetInputLimits(Values{-1, -1}, Values{1, 1}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+		if _, err := controller.Calculate(Values{1}, Values{1, 2}); !errors.Is(err, ErrSlicessMustBeSameLength) {
+			t.Errorf("Expected ErrSlicessMustBeSameLength, got %v", err)
+		}
+		if _, err := controller.Calculate(Values{1}, Values{1}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package feedback
+
+import (
+	"math"
+
+	"control/linalg"
+)
+
+const (
+	// riccatiMaxIterations bounds the iterations of the Riccati solvers
+	riccatiMaxIterations = 100
+
+	// riccatiTolerance is the relative change at which the Riccati iterations have converged
+	riccatiTolerance = 1e-13
+
+	// riccatiResidualTolerance is the largest relative residual accepted for a solution
+	riccatiResidualTolerance = 1e-6
+)
+
+// LQR computes the optimal gain K for the continuous-time system dx/dt = A x + B u.
+//
+// The control law u = -K x minimizes the cost integral of x^T Q x + u^T R u. Q must be
+// symmetric positive semi-definite and R symmetric positive definite. The returned gain has one
+// row per input and one column per state, so for a single-input system its only row can be
+// passed to New, which applies u = K (setpoint - measurement).
+//
+// Returns ErrDimensionMismatch if the matrix sizes are inconsistent, ErrSingularMatrix if R is
+// singular and ErrNoSolution if the system is not stabilizable.
+func LQR(a, b, q, r Matrix) (Matrix, error) {
+	p, err := SolveCARE(a, b, q, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// K = R^-1 B^T P
+	return r.Solve(b.Transpose().Mul(p))
+}
+
+// DiscreteLQR computes the optimal gain K for the discrete-time system x[k+1] = A x[k] + B u[k].
+//
+// The control law u = -K x minimizes the sum of x^T Q x + u^T R u. The requirements and errors
+// are the same as for LQR.
+func DiscreteLQR(a, b, q, r Matrix) (Matrix, error) {
+	p, err := SolveDARE(a, b, q, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// K = (R + B^T P B)^-1 B^T P A
+	btp := b.Transpose().Mul(p)
+	return r.Add(btp.Mul(b)).Solve(btp.Mul(a))
+}
+
+// NewLQR creates a FullStateFeedback controller for a single-input continuous-time system using
+// the gain computed by LQR and the given options.
+//
+// Returns ErrMultipleInputs if B has more than one column, in which case NewMultiInputLQR
+// applies the full gain matrix, or any error from LQR.
+func NewLQR(a, b, q, r Matrix, opts ...Option) (*FullStateFeedback, error) {
+	k, err := LQR(a, b, q, r)
+	return newSingleInput(k, err, opts)
+}
+
+// NewDiscreteLQR creates a FullStateFeedback controller for a single-input discrete-time system
+// using the gain computed by DiscreteLQR and the given options.
+//
+// Returns ErrMultipleInputs if B has more than one column, in which case
+// NewMultiInputDiscreteLQR applies the full gain matrix, or any error from DiscreteLQR.
+func NewDiscreteLQR(a, b, q, r Matrix, opts ...Option) (*FullStateFeedback, error) {
+	k, err := DiscreteLQR(a, b, q, r)
+	return newSingleInput(k, err, opts)
+}
+
+// newSingleInput creates a FullStateFeedback controller from a gain matrix with a single row.
+func newSingleInput(k Matrix, err error, opts []Option) (*FullStateFeedback, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(k) != 1 {
+		return nil, ErrMultipleInputs
+	}
+	return New(Values(k[0]), opts...), nil
+}
+
+// SolveCARE returns the stabilizing solution P of the continuous algebraic Riccati equation
+//
+//	A^T P + P A - P B R^-1 B^T P + Q = 0
+//
+// The solution is found from the stable invariant subspace of the Hamiltonian matrix using
+// the matrix sign function, which converges quadratically and needs no eigenvalue solver.
+func SolveCARE(a, b, q, r Matrix) (Matrix, error) {
+	n, err := validateRiccati(a, b, q, r)
+	if err != nil {
+		return nil, err
+	}
+	g, err := inputWeight(b, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hamiltonian H = [A, -G; -Q, -A^T]
+	h := linalg.New(2*n, 2*n)
+	for i := range n {
+		for j := range n {
+			h[i][j] = a[i][j]
+			h[i][n+j] = -g[i][j]
+			h[n+i][j] = -q[i][j]
+			h[n+i][n+j] = -a[j][i]
+		}
+	}
+
+	// Newton iteration for sign(H) with determinant scaling
+	w := h
+	converged := false
+	for range riccatiMaxIterations {
+		inv, err := w.Inverse()
+		if err != nil {
+			// An eigenvalue on the imaginary axis: no stabilizing solution
+			return nil, ErrNoSolution
+		}
+		c := math.Exp(-w.LogDet() / float64(2*n))
+		next := w.Scale(c).Add(inv.Scale(1 / c)).Scale(0.5)
+		if !next.IsFinite() {
+			return nil, ErrNoSolution
+		}
+		change := next.Sub(w).Norm()
+		w = next
+		if change <= riccatiTolerance*w.Norm() {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return nil, ErrNoSolution
+	}
+
+	// The stable subspace [I; P] satisfies (W + I) [I; P] = 0, so
+	// [W12; W22 + I] P = -[W11 + I; W21], solved in the least squares sense
+	lhs := linalg.New(2*n, n)
+	rhs := linalg.New(2*n, n)
+	for i := range n {
+		for j := range n {
+			lhs[i][j] = w[i][n+j]
+			lhs[n+i][j] = w[n+i][n+j]
+			rhs[i][j] = -w[i][j]
+			rhs[n+i][j] = -w[n+i][j]
+		}
+		lhs[n+i][i] += 1
+		rhs[i][i] -= 1
+	}
+	lhsT := lhs.Transpose()
+	p, err := lhsT.Mul(lhs).Solve(lhsT.Mul(rhs))
+	if err != nil {
+		return nil, ErrNoSolution
+	}
+	p = p.Symmetrize()
+
+	// Reject solutions that do not satisfy the equation, such as for unstabilizable systems
+	residual := a.Transpose().Mul(p).Add(p.Mul(a)).Sub(p.Mul(g).Mul(p)).Add(q)
+	if !p.IsFinite() || residual.Norm() > riccatiResidualTolerance*max(1, p.Norm()*(a.Norm()+g.Norm()*p.Norm())+q.Norm()) {
+		return nil, ErrNoSolution
+	}
+	return p, nil
+}
+
+// SolveDARE returns the stabilizing solution P of the discrete algebraic Riccati equation
+//
+//	P = A^T P A - A^T P B (R + B^T P B)^-1 B^T P A + Q
+//
+// The solution is found with the structure-preserving doubling algorithm, which converges
+// quadratically.
+func SolveDARE(a, b, q, r Matrix) (Matrix, error) {
+	n, err := validateRiccati(a, b, q, r)
+	if err != nil {
+		return nil, err
+	}
+	g, err := inputWeight(b, r)
+	if err != nil {
+		return nil, err
+	}
+
+	ak, gk, hk := a.Clone(), g, q.Clone()
+	identity := linalg.Identity(n)
+	converged := false
+	for range riccatiMaxIterations {
+		// W = I + G H; the updates use W^-1 A and W^-1 G
+		w := identity.Add(gk.Mul(hk))
+		wa, err := w.Solve(ak)
+		if err != nil {
+			return nil, ErrNoSolution
+		}
+		wg, err := w.Solve(gk)
+		if err != nil {
+			return nil, ErrNoSolution
+		}
+
+		nextH := hk.Add(ak.Transpose().Mul(hk).Mul(wa)).Symmetrize()
+		gk = gk.Add(ak.Mul(wg).Mul(ak.Transpose())).Symmetrize()
+		ak = ak.Mul(wa)
+		if !nextH.IsFinite() || !gk.IsFinite() || !ak.IsFinite() {
+			return nil, ErrNoSolution
+		}
+
+		change := nextH.Sub(hk).Norm()
+		hk = nextH
+		if change <= riccatiTolerance*max(hk.Norm(), 1e-300) {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return nil, ErrNoSolution
+	}
+
+	// Reject solutions that do not satisfy the equation
+	p := hk
+	btp := b.Transpose().Mul(p)
+	correction, err := r.Add(btp.Mul(b)).Solve(btp.Mul(a))
+	if err != nil {
+		return nil, ErrNoSolution
+	}
+	residual := a.Transpose().Mul(p).Mul(a).Sub(a.Transpose().Mul(btp.Transpose()).Mul(correction)).Add(q).Sub(p)
+	if residual.Norm() > riccatiResidualTolerance*max(1, p.Norm()*(1+a.Norm()*a.Norm())+q.Norm()) {
+		return nil, ErrNoSolution
+	}
+	return p, nil
+}
+
+// validateRiccati checks that A is n by n, B is n by m, Q is n by n and R is m by m, and returns n.
+func validateRiccati(a, b, q, r Matrix) (int, error) {
+	n, cols := a.Dims()
+	if n == 0 || cols != n {
+		return 0, ErrDimensionMismatch
+	}
+	rows, m := b.Dims()
+	if rows != n || m <= 0 {
+		return 0, ErrDimensionMismatch
+	}
+	if rows, cols := q.Dims(); rows != n || cols != n {
+		return 0, ErrDimensionMismatch
+	}
+	if rows, cols := r.Dims(); rows != m || cols != m {
+		return 0, ErrDimensionMismatch
+	}
+	return n, nil
+}
+
+// inputWeight returns G = B R^-1 B^T.
+func inputWeight(b, r Matrix) (Matrix, error) {
+	rInvBt, err := r.Solve(b.Transpose())
+	if err != nil {
+		return nil, err
+	}
+	return b.Mul(rInvBt).Symmetrize(), nil
+}
@@ -0,0 +1,257 @@
+package feedback
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"control/linalg"
+)
+
+// cartPole returns the linearized inverted pendulum on a cart, with states cart position,
+// cart velocity, pendulum angle and pendulum angular velocity, and the cart force as input.
+func cartPole() (a, b Matrix) {
+	const (
+		cartMass     = 0.5
+		pendulumMass = 0.2
+		friction     = 0.1
+		length       = 0.3
+		inertia      = 0.006
+		gravity      = 9.8
+	)
+	p := inertia*(cartMass+pendulumMass) + cartMass*pendulumMass*length*length
+
+	a = Matrix{
+		{0, 1, 0, 0},
+		{0, -(inertia + pendulumMass*length*length) * friction / p, pendulumMass * pendulumMass * gravity * length * length / p, 0},
+		{0, 0, 0, 1},
+		{0, -(pendulumMass * length * friction) / p, pendulumMass * gravity * length * (cartMass + pendulumMass) / p, 0},
+	}
+	b = Matrix{
+		{0},
+		{(inertia + pendulumMass*length*length) / p},
+		{0},
+		{pendulumMass * length / p},
+	}
+	return a, b
+}
+
+func assertGain(t *testing.T, got Matrix, expected []float64, tolerance float64) {
+	t.Helper()
+	if len(got) != 1 || len(got[0]) != len(expected) {
+		t.Fatalf("Expected a 1x%d gain, got %v", len(expected), got)
+	}
+	for i, v := range expected {
+		if math.Abs(got[0][i]-v) > tolerance {
+			t.Errorf("K[%d] = %f, expected %f", i, got[0][i], v)
+		}
+	}
+}
+
+func TestLQR(t *testing.T) {
+	t.Run("Double integrator", func(t *testing.T) {
+		a := Matrix{{0, 1}, {0, 0}}
+		b := Matrix{{0}, {1}}
+		k, err := LQR(a, b, linalg.Diagonal(1, 1), Matrix{{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGain(t, k, []float64{1, math.Sqrt(3)}, 1e-9)
+
+		p, _ := SolveCARE(a, b, linalg.Diagonal(1, 1), Matrix{{1}})
+		expected := Matrix{{math.Sqrt(3), 1}, {1, math.Sqrt(3)}}
+		for i := range expected {
+			for j := range expected[i] {
+				if math.Abs(p[i][j]-expected[i][j]) > 1e-9 {
+					t.Errorf("P[%d][%d] = %f, expected %f", i, j, p[i][j], expected[i][j])
+				}
+			}
+		}
+	})
+
+	t.Run("Cart-pole", func(t *testing.T) {
+		a, b := cartPole()
+
+		k, err := LQR(a, b, linalg.Diagonal(1, 0, 1, 0), Matrix{{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGain(t, k, []float64{-1.0000, -1.6567, 18.6854, 3.4594}, 1e-3)
+
+		k, err = LQR(a, b, linalg.Diagonal(5000, 0, 100, 0), Matrix{{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGain(t, k, []float64{-70.7107, -37.8345, 105.5298, 20.9238}, 1e-3)
+	})
+
+	t.Run("Multiple inputs", func(t *testing.T) {
+		a := Matrix{{0, 1}, {2, -1}}
+		b := Matrix{{1, 0}, {0, 1}}
+		q := linalg.Diagonal(1, 2)
+		r := linalg.Diagonal(1, 0.5)
+		p, err := SolveCARE(a, b, q, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Check the Riccati residual directly
+		rInv, _ := r.Inverse()
+		residual := a.Transpose().Mul(p).Add(p.Mul(a)).Sub(p.Mul(b).Mul(rInv).Mul(b.Transpose()).Mul(p)).Add(q)
+		if residual.Norm() > 1e-9 {
+			t.Errorf("Expected zero residual, got %e", residual.Norm())
+		}
+
+		k, err := LQR(a, b, q, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(k) != 2 || len(k[0]) != 2 {
+			t.Fatalf("Expected a 2x2 gain, got %v", k)
+		}
+		if _, err := NewLQR(a, b, q, r); !errors.Is(err, ErrMultipleInputs) {
+			t.Errorf("Expected ErrMultipleInputs, got %v", err)
+		}
+	})
+
+	t.Run("Controller", func(t *testing.T) {
+		a, b := cartPole()
+		controller, err := NewLQR(a, b, linalg.Diagonal(1, 0, 1, 0), Matrix{{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Simulate the closed loop from a tilted pendulum
+		x := Values{0, 0, 0.1, 0}
+		dt := 0.001
+		for range 10000 {
+			u, err := controller.Calculate(Values{0, 0, 0, 0}, x)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dx := make(Values, len(x))
+			for i := range x {
+				for j := range x {
+					dx[i] += a[i][j] * x[j]
+				}
+				dx[i] += b[i][0] * u
+			}
+			for i := range x {
+				x[i] += dx[i] * dt
+			}
+		}
+		for i, v := range x {
+			if math.Abs(v) > 1e-3 {
+				t.Errorf("State %d did not converge: %f", i, v)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		a := Matrix{{0, 1}, {0, 0}}
+		b := Matrix{{0}, {1}}
+		q := linalg.Diagonal(1, 1)
+		r := Matrix{{1}}
+
+		tests := []struct {
+			name       string
+			a, b, q, r Matrix
+			expected   error
+		}{
+			{"Non-square A", Matrix{{0, 1}}, b, q, r, ErrDimensionMismatch},
+			{"Wrong B rows", a, Matrix{{1}}, q, r, ErrDimensionMismatch},
+			{"Wrong Q size", a, b, linalg.Diagonal(1), r, ErrDimensionMismatch},
+			{"Wrong R size", a, b, q, linalg.Diagonal(1, 1), ErrDimensionMismatch},
+			{"Ragged A", Matrix{{0, 1}, {0}}, b, q, r, ErrDimensionMismatch},
+			{"Ragged B", a, Matrix{{0}, {1, 0}}, q, r, ErrDimensionMismatch},
+			{"Ragged Q", a, b, Matrix{{1, 0}, {1}}, r, ErrDimensionMismatch},
+			{"Ragged R", Matrix{{0, 1}, {0, 0}}, Matrix{{0, 0}, {1, 0}}, q, Matrix{{1, 0}, {0}}, ErrDimensionMismatch},
+			{"Singular R", a, b, q, Matrix{{0}}, ErrSingularMatrix},
+			{"Unstabilizable", Matrix{{1, 0}, {0, 1}}, Matrix{{1}, {0}}, q, r, ErrNoSolution},
+		}
+		for _, tt := range tests {
+			if _, err := LQR(tt.a, tt.b, tt.q, tt.r); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+			}
+			if tt.expected != ErrDimensionMismatch {
+				continue
+			}
+			if _, err := SolveCARE(tt.a, tt.b, tt.q, tt.r); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: SolveCARE expected %v, got %v", tt.name, tt.expected, err)
+			}
+			if _, err := SolveDARE(tt.a, tt.b, tt.q, tt.r); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: SolveDARE expected %v, got %v", tt.name, tt.expected, err)
+			}
+		}
+	})
+}
+
+func TestDiscreteLQR(t *testing.T) {
+	t.Run("Scalar", func(t *testing.T) {
+		// P^2 = P + 1 for A = B = Q = R = 1, so P is the golden ratio
+		phi := (1 + math.Sqrt(5)) / 2
+		p, err := SolveDARE(Matrix{{1}}, Matrix{{1}}, Matrix{{1}}, Matrix{{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(p[0][0]-phi) > 1e-12 {
+			t.Errorf("Expected P = %f, got %f", phi, p[0][0])
+		}
+
+		k, _ := DiscreteLQR(Matrix{{1}}, Matrix{{1}}, Matrix{{1}}, Matrix{{1}})
+		assertGain(t, k, []float64{phi / (1 + phi)}, 1e-12)
+	})
+
+	t.Run("Approaches the continuous gain for small dt", func(t *testing.T) {
+		a, b := cartPole()
+		q := linalg.Diagonal(1, 0, 1, 0)
+		r := Matrix{{1}}
+		continuous, err := LQR(a, b, q, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Forward Euler discretization with the cost scaled by dt
+		dt := 1e-4
+		ad := linalg.Identity(4).Add(a.Scale(dt))
+		bd := b.Scale(dt)
+		discrete, err := DiscreteLQR(ad, bd, q.Scale(dt), r.Scale(dt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range continuous[0] {
+			if math.Abs(discrete[0][i]-continuous[0][i]) > 0.01*math.Abs(continuous[0][i]) {
+				t.Errorf("K[%d]: discrete %f, continuous %f", i, discrete[0][i], continuous[0][i])
+			}
+		}
+	})
+
+	t.Run("Double integrator controller", func(t *testing.T) {
+		dt := 0.1
+		a := Matrix{{1, dt}, {0, 1}}
+		b := Matrix{{0.5 * dt * dt}, {dt}}
+		controller, err := NewDiscreteLQR(a, b, linalg.Diagonal(1, 1), Matrix{{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		x := Values{1, 0}
+		for range 200 {
+			u, _ := controller.Calculate(Values{0, 0}, x)
+			x = Values{
+				a[0][0]*x[0] + a[0][1]*x[1] + b[0][0]*u,
+				a[1][0]*x[0] + a[1][1]*x[1] + b[1][0]*u,
+			}
+		}
+		if math.Abs(x[0]) > 1e-3 || math.Abs(x[1]) > 1e-3 {
+			t.Errorf("Expected the state to converge, got %v", x)
+		}
+	})
+
+	t.Run("Unstabilizable", func(t *testing.T) {
+		_, err := DiscreteLQR(Matrix{{2, 0}, {0, 0.5}}, Matrix{{0}, {1}}, linalg.Diagonal(1, 1), Matrix{{1}})
+		if !errors.Is(err, ErrNoSolution) {
+			t.Errorf("Expected ErrNoSolution, got %v", err)
+		}
+	})
+}
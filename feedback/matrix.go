@@ -0,0 +1,27 @@
+package feedback
+
+import "control/linalg"
+
+// Matrix is a dense matrix stored as a slice of rows.
+type Matrix = linalg.Matrix
+
+// controllabilityMatrix returns [B, A B, A^2 B, ..., A^(n-1) B].
+func controllabilityMatrix(a, b Matrix) Matrix {
+	n, m := b.Dims()
+	result := linalg.New(n, n*m)
+	block := b
+	for k := range n {
+		for i := range n {
+			copy(result[i][k*m:(k+1)*m], block[i])
+		}
+		block = a.Mul(block)
+	}
+	return result
+}
+
+// controllable reports whether the pair (A, B) is controllable, that is whether the
+// controllability matrix has full row rank.
+func controllable(a, b Matrix) bool {
+	n, _ := a.Dims()
+	return controllabilityMatrix(a, b).Rank() == n
+}
@@ -0,0 +1,121 @@
+package feedback
+
+// MultiInputFeedback is full state feedback for plants with several inputs, such as a
+// differential drive or a two-axis gimbal. The gain is an m by n matrix with one row per input
+// and one column per state, and the output is the vector u = K (setpoint - measurement).
+type MultiInputFeedback struct {
+	gain   Matrix
+	inputs int
+	states int
+	output outputStage
+}
+
+// NewMultiInput creates a new MultiInputFeedback controller with the specified gain matrix and
+// options. The gain is copied, so later changes to the argument do not affect the controller.
+//
+// Returns ErrDimensionMismatch if the gain is empty, its rows have different lengths or input
+// limits do not have one value per input, and ErrInvalidLimits if a minimum exceeds its maximum.
+func NewMultiInput(gain Matrix, opts ...Option) (*MultiInputFeedback, error) {
+	inputs, states := gain.Dims()
+	if inputs == 0 || states <= 0 {
+		return nil, ErrDimensionMismatch
+	}
+	output := newOutputStage(inputs, opts)
+	if output.err != nil {
+		return nil, output.err
+	}
+	return &MultiInputFeedback{
+		gain:   gain.Clone(),
+		inputs: inputs,
+		states: states,
+		output: output,
+	}, nil
+}
+
+// NewMultiInputLQR creates a MultiInputFeedback controller for a continuous-time system using
+// the gain computed by LQR.
+func NewMultiInputLQR(a, b, q, r Matrix, opts ...Option) (*MultiInputFeedback, error) {
+	k, err := LQR(a, b, q, r)
+	if err != nil {
+		return nil, err
+	}
+	return NewMultiInput(k, opts...)
+}
+
+// NewMultiInputDiscreteLQR creates a MultiInputFeedback controller for a discrete-time system
+// using the gain computed by DiscreteLQR.
+func NewMultiInputDiscreteLQR(a, b, q, r Matrix, opts ...Option) (*MultiInputFeedback, error) {
+	k, err := DiscreteLQR(a, b, q, r)
+	if err != nil {
+		return nil, err
+	}
+	return NewMultiInput(k, opts...)
+}
+
+// Calculate computes the control output vector based on the full state feedback, applying
+// voltage compensation and the per-input limits.
+//
+// Returns ErrSlicessMustBeSameLength if the setpoint and measurement have different lengths and
+// ErrDimensionMismatch if their length does not match the number of states.
+func (mf *MultiInputFeedback) Calculate(setpoint, measurement Values) (Values, error) {
+	output := make(Values, mf.inputs)
+	if err := mf.CalculateInto(output, setpoint, measurement); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// CalculateInto is like Calculate, but writes the control output vector into a caller-owned
+// buffer with one value per input and does not allocate.
+//
+// Returns ErrDimensionMismatch if the output buffer has the wrong length, or any error from
+// Calculate.
+func (mf *MultiInputFeedback) CalculateInto(output, setpoint, measurement Values) error {
+	if len(setpoint) != len(measurement) {
+		return ErrSlicessMustBeSameLength
+	}
+	if len(setpoint) != mf.states || len(output) != mf.inputs {
+		return ErrDimensionMismatch
+	}
+
+	scale := mf.output.begin()
+	for i, row := range mf.gain {
+		u, _ := errorProduct(row, setpoint, measurement)
+		output[i] = mf.output.limit(i, u*scale)
+	}
+	return nil
+}
+
+// SetInputLimits sets the minimum and maximum output of each input.
+//
+// Returns ErrDimensionMismatch if the limits do not have one value per input and
+// ErrInvalidLimits if a minimum exceeds its maximum.
+func (mf *MultiInputFeedback) SetInputLimits(min, max Values) error {
+	return mf.output.setLimits(min, max)
+}
+
+// GetInputLimits returns copies of the per-input limits.
+func (mf *MultiInputFeedback) GetInputLimits() (min, max Values) {
+	return mf.output.limits()
+}
+
+// IsSaturated reports whether any output of the last call to Calculate was limited.
+func (mf *MultiInputFeedback) IsSaturated() bool {
+	return mf.output.saturated
+}
+
+// GetGain returns a copy of the gain matrix.
+func (mf *MultiInputFeedback) GetGain() Matrix {
+	return mf.gain.Clone()
+}
+
+// Inputs returns the number of plant inputs, which is the length of the output vector.
+func (mf *MultiInputFeedback) Inputs() int {
+	return mf.inputs
+}
+
+// States returns the number of states, which is the expected length of the setpoint and
+// measurement.
+func (mf *MultiInputFeedback) States() int {
+	return mf.states
+}
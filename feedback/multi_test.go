@@ -0,0 +1,121 @@
+package feedback
+
+import (
+	"errors"
+	"testing"
+
+	"control/linalg"
+)
+
+func TestMultiInputFeedback(t *testing.T) {
+	t.Run("Calculate", func(t *testing.T) {
+		// Differential drive: left and right voltages from position and heading errors
+		controller, err := NewMultiInput(Matrix{
+			{2.0, -1.0, 0.5},
+			{2.0, 1.0, 0.5},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if controller.Inputs() != 2 || controller.States() != 3 {
+			t.Fatalf("Expected 2 inputs and 3 states, got %d and %d", controller.Inputs(), controller.States())
+		}
+
+		output, err := controller.Calculate(Values{1.0, 0.5, 0.0}, Values{0.5, 0.0, 1.0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := Values{2.0*0.5 - 1.0*0.5 - 0.5*1.0, 2.0*0.5 + 1.0*0.5 - 0.5*1.0}
+		for i := range expected {
+			if !almostEqual(output[i], expected[i], 1e-12) {
+				t.Errorf("Output[%d] = %f, expected %f", i, output[i], expected[i])
+			}
+		}
+	})
+
+	t.Run("Single row matches FullStateFeedback", func(t *testing.T) {
+		gain := Values{1.5, 0.3}
+		multi, _ := NewMultiInput(Matrix{gain})
+		single := New(gain)
+
+		setpoint, measurement := Values{10.0, 0.0}, Values{8.5, 1.2}
+		expected, _ := single.Calculate(setpoint, measurement)
+		output, err := multi.Calculate(setpoint, measurement)
+		if err != nil || len(output) != 1 || output[0] != expected {
+			t.Errorf("Expected [%f], got %v (%v)", expected, output, err)
+		}
+	})
+
+	t.Run("Gain is copied", func(t *testing.T) {
+		gain := Matrix{{1.0, 2.0}}
+		controller, _ := NewMultiInput(gain)
+		gain[0][0] = 100
+		controller.GetGain()[0][1] = 100
+
+		output, _ := controller.Calculate(Values{1, 1}, Values{0, 0})
+		if output[0] != 3.0 {
+			t.Errorf("Expected 3, got %f", output[0])
+		}
+	})
+
+	t.Run("LQR", func(t *testing.T) {
+		a := Matrix{{0, 1}, {2, -1}}
+		b := Matrix{{1, 0}, {0, 1}}
+		q := linalg.Diagonal(1, 2)
+		r := linalg.Diagonal(1, 0.5)
+		k, _ := LQR(a, b, q, r)
+		controller, err := NewMultiInputLQR(a, b, q, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, _ := controller.Calculate(Values{0, 0}, Values{1, -1})
+		for i := range output {
+			expected := -k[i][0] + k[i][1]
+			if !almostEqual(output[i], expected, 1e-12) {
+				t.Errorf("Output[%d] = %f, expected %f", i, output[i], expected)
+			}
+		}
+
+		if _, err := NewMultiInputDiscreteLQR(a, b, q, Matrix{{1}}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		invalid := map[string]Matrix{
+			"Empty":        {},
+			"Empty row":    {{}},
+			"Ragged":       {{1, 2}, {3}},
+			"Nil":          nil,
+			"Ragged first": {{1}, {2, 3}},
+		}
+		for name, gain := range invalid {
+			if _, err := NewMultiInput(gain); !errors.Is(err, ErrDimensionMismatch) {
+				t.Errorf("%s: expected ErrDimensionMismatch, got %v", name, err)
+			}
+		}
+
+		controller, _ := NewMultiInput(Matrix{{1, 2}, {3, 4}})
+		if _, err := controller.Calculate(Values{1}, Values{1, 2}); !errors.Is(err, ErrSlicessMustBeSameLength) {
+			t.Errorf("Expected ErrSlicessMustBeSameLength, got %v", err)
+		}
+		if _, err := controller.Calculate(Values{1, 2, 3}, Values{1, 2, 3}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+}
+
+func BenchmarkMultiInputFeedbackCalculate(b *testing.B) {
+	controller, _ := NewMultiInput(Matrix{
+		{1.5, 0.3, 2.1, 0.8},
+		{0.2, 1.1, 0.4, 1.6},
+	})
+	setpoint := Values{10.0, 5.0, 2.0, 8.0}
+	measurement := Values{8.5, 6.0, 1.5, 7.2}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		controller.Calculate(setpoint, measurement)
+	}
+}
@@ -0,0 +1,173 @@
+package feedback
+
+import (
+	"errors"
+	"slices"
+)
+
+// Observer is a Luenberger observer that estimates the full state of a discrete-time plant from
+// its inputs and measured outputs. Each update corrects the prediction with the output error
+// and advances it by one sample:
+//
+//	x[k+1] = A x[k] + B u[k] + L (y[k] - C x[k] - D u[k])
+//
+// The estimation error decays with the eigenvalues of A - L C, which DesignObserverGain places.
+type Observer struct {
+	model    *StateSpace
+	gain     Matrix
+	estimate Values
+	initial  Values
+
+	// Scratch buffers that keep Update free of allocations
+	next      Values
+	predicted Values
+}
+
+// NewObserver creates an observer for a discrete-time model with an n by p gain L, where n is
+// the number of states and p the number of outputs. The estimate starts at zero.
+//
+// Returns ErrNotDiscrete for a continuous-time model and ErrDimensionMismatch if the gain has
+// the wrong size.
+func NewObserver(model *StateSpace, gain Matrix) (*Observer, error) {
+	if !model.IsDiscrete() {
+		return nil, ErrNotDiscrete
+	}
+	if rows, cols := gain.Dims(); rows != model.States() || cols != model.Outputs() {
+		return nil, ErrDimensionMismatch
+	}
+	return &Observer{
+		model:     model,
+		gain:      gain.Clone(),
+		estimate:  make(Values, model.States()),
+		initial:   make(Values, model.States()),
+		next:      make(Values, model.States()),
+		predicted: make(Values, model.Outputs()),
+	}, nil
+}
+
+// DesignObserverGain computes the gain L that places the eigenvalues of A - L C, which govern
+// how fast the estimation error decays. By duality this is pole placement on (A^T, C^T), so the
+// poles must satisfy the same conditions as for Place. Observer poles are usually chosen a few
+// times faster than the closed-loop controller poles.
+//
+// Returns ErrUnobservable if the states cannot be reconstructed from the outputs, or any other
+// error from Place.
+func DesignObserverGain(model *StateSpace, poles []complex128) (Matrix, error) {
+	gain, err := Place(model.a.Transpose(), model.c.Transpose(), poles)
+	if errors.Is(err, ErrUncontrollable) {
+		return nil, ErrUnobservable
+	}
+	if err != nil {
+		return nil, err
+	}
+	return gain.Transpose(), nil
+}
+
+// Update corrects the estimate with the measured output y produced while the input u was
+// applied, and advances it to the next sample. It does not allocate.
+//
+// Returns ErrDimensionMismatch if u or y has the wrong length.
+func (o *Observer) Update(u, y Values) error {
+	if len(u) != o.model.Inputs() || len(y) != o.model.Outputs() {
+		return ErrDimensionMismatch
+	}
+	o.model.outputInto(o.predicted, o.estimate, u)
+	o.model.propagateInto(o.next, o.estimate, u)
+	for i := range o.next {
+		for j := range y {
+			o.next[i] += o.gain[i][j] * (y[j] - o.predicted[j])
+		}
+	}
+	o.estimate, o.next = o.next, o.estimate
+	return nil
+}
+
+// GetEstimate returns a copy of the current state estimate.
+func (o *Observer) GetEstimate() Values {
+	return slices.Clone(o.estimate)
+}
+
+// SetEstimate sets the state estimate, for example from a known starting position. The value is
+// also used by Reset.
+//
+// Returns ErrDimensionMismatch if the estimate has the wrong length.
+func (o *Observer) SetEstimate(estimate Values) error {
+	if len(estimate) != o.model.States() {
+		return ErrDimensionMismatch
+	}
+	copy(o.estimate, estimate)
+	copy(o.initial, estimate)
+	return nil
+}
+
+// GetGain returns a copy of the observer gain.
+func (o *Observer) GetGain() Matrix {
+	return o.gain.Clone()
+}
+
+// Reset returns the estimate to its initial value.
+func (o *Observer) Reset() {
+	copy(o.estimate, o.initial)
+}
+
+// Regulator combines a FullStateFeedback controller with an Observer, so that a single-input
+// plant can be controlled when only some of its states are measured. The controller acts on the
+// observer's estimate, and the applied output is fed back into the observer.
+type Regulator struct {
+	controller *FullStateFeedback
+	observer   *Observer
+	input      Values
+}
+
+// NewRegulator creates a regulator from a controller and an observer for the same plant.
+//
+// Returns ErrMultipleInputs if the observer's model has more than one input and
+// ErrDimensionMismatch if the controller gain does not have one value per state.
+func NewRegulator(controller *FullStateFeedback, observer *Observer) (*Regulator, error) {
+	if observer.model.Inputs() != 1 {
+		return nil, ErrMultipleInputs
+	}
+	if len(controller.gain) != observer.model.States() {
+		return nil, ErrDimensionMismatch
+	}
+	return &Regulator{
+		controller: controller,
+		observer:   observer,
+		input:      make(Values, 1),
+	}, nil
+}
+
+// Calculate computes the control output from the state estimate, then updates the observer
+// with the measured output and the computed control output. It does not allocate.
+//
+// Returns ErrSlicessMustBeSameLength if the setpoint does not have one value per state and
+// ErrDimensionMismatch if the measurement does not have one value per output.
+func (r *Regulator) Calculate(setpoint, measurement Values) (float64, error) {
+	if len(measurement) != r.observer.model.Outputs() {
+		return 0, ErrDimensionMismatch
+	}
+	output, err := r.controller.Calculate(setpoint, r.observer.estimate)
+	if err != nil {
+		return 0, err
+	}
+	r.input[0] = output
+	if err := r.observer.Update(r.input, measurement); err != nil {
+		return 0, err
+	}
+	return output, nil
+}
+
+// GetEstimate returns a copy of the observer's current state estimate.
+func (r *Regulator) GetEstimate() Values {
+	return r.observer.GetEstimate()
+}
+
+// GetObserver returns the observer.
+func (r *Regulator) GetObserver() *Observer {
+	return r.observer
+}
+
+// Reset returns the observer estimate to its initial value.
+func (r *Regulator) Reset() {
+	r.observer.Reset()
+}
@@ -0,0 +1,168 @@
+package feedback
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"control/linalg"
+)
+
+// positionOnly returns a discretized double integrator that only measures position.
+func positionOnly(t *testing.T, dt float64) *StateSpace {
+	t.Helper()
+	plant, err := NewStateSpace(Matrix{{0, 1}, {0, 0}}, Matrix{{0}, {1}}, Matrix{{1, 0}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := plant.Discretize(dt, ZeroOrderHold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+func TestObserver(t *testing.T) {
+	model := positionOnly(t, 0.01)
+
+	t.Run("Gain places the error poles", func(t *testing.T) {
+		poles := []complex128{0.8, 0.85}
+		gain, err := DesignObserverGain(model, poles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(gain) != 2 || len(gain[0]) != 1 {
+			t.Fatalf("Expected a 2x1 gain, got %v", gain)
+		}
+
+		a, _, c, _ := model.Matrices()
+		errorDynamics, _ := NewDiscreteStateSpace(a.Sub(gain.Mul(c)), linalg.New(2, 1), nil, nil, 0.01)
+		got, _ := errorDynamics.Poles()
+		sortPoles(got)
+		for i := range poles {
+			if math.Abs(real(got[i])-real(poles[i])) > 1e-9 || math.Abs(imag(got[i])) > 1e-9 {
+				t.Errorf("Pole %d = %v, expected %v", i, got[i], poles[i])
+			}
+		}
+	})
+
+	t.Run("Estimate converges to the true state", func(t *testing.T) {
+		gain, _ := DesignObserverGain(model, []complex128{0.8, 0.85})
+		observer, err := NewObserver(model, gain)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The plant starts moving while the observer believes it is at rest at the origin
+		x := Values{1.0, 2.0}
+		for k := range 200 {
+			u := Values{math.Sin(float64(k) * 0.05)}
+			y, _ := model.Output(x, u)
+			if err := observer.Update(u, y); err != nil {
+				t.Fatal(err)
+			}
+			x, _ = model.Step(x, u)
+		}
+		estimate := observer.GetEstimate()
+		for i := range x {
+			if math.Abs(estimate[i]-x[i]) > 1e-6 {
+				t.Errorf("State %d: estimate %f, actual %f", i, estimate[i], x[i])
+			}
+		}
+
+		observer.Reset()
+		if estimate := observer.GetEstimate(); estimate[0] != 0 || estimate[1] != 0 {
+			t.Errorf("Expected a zero estimate after reset, got %v", estimate)
+		}
+		_ = observer.SetEstimate(Values{1, 2})
+		observer.Reset()
+		if estimate := observer.GetEstimate(); estimate[0] != 1 || estimate[1] != 2 {
+			t.Errorf("Expected the initial estimate after reset, got %v", estimate)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		plant, _ := NewStateSpace(Matrix{{0, 1}, {0, 0}}, Matrix{{0}, {1}}, Matrix{{1, 0}}, nil)
+		if _, err := NewObserver(plant, Matrix{{1}, {1}}); !errors.Is(err, ErrNotDiscrete) {
+			t.Errorf("Expected ErrNotDiscrete, got %v", err)
+		}
+		if _, err := NewObserver(model, Matrix{{1, 1}}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+
+		velocity, _ := NewDiscreteStateSpace(Matrix{{1, 0.01}, {0, 1}}, Matrix{{0}, {0.01}}, Matrix{{0, 1}}, nil, 0.01)
+		if _, err := DesignObserverGain(velocity, []complex128{0.8, 0.85}); !errors.Is(err, ErrUnobservable) {
+			t.Errorf("Expected ErrUnobservable, got %v", err)
+		}
+
+		observer, _ := NewObserver(model, Matrix{{1}, {1}})
+		if err := observer.Update(Values{0}, Values{0, 0}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+		if err := observer.SetEstimate(Values{0}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+}
+
+func TestRegulator(t *testing.T) {
+	dt := 0.01
+	model := positionOnly(t, dt)
+	a, b, _, _ := model.Matrices()
+
+	t.Run("Reaches the setpoint from position alone", func(t *testing.T) {
+		controller, err := NewDiscreteLQR(a, b, linalg.Diagonal(10, 1), Matrix{{0.1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gain, _ := DesignObserverGain(model, []complex128{0.7, 0.75})
+		observer, _ := NewObserver(model, gain)
+		regulator, err := NewRegulator(controller, observer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		x := Values{0, 0}
+		setpoint := Values{1, 0}
+		for range 1000 {
+			y, _ := model.Output(x, Values{0})
+			u, err := regulator.Calculate(setpoint, y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			x, _ = model.Step(x, Values{u})
+		}
+		if math.Abs(x[0]-1) > 1e-3 || math.Abs(x[1]) > 1e-3 {
+			t.Errorf("Expected to settle at [1, 0], got %v", x)
+		}
+		if estimate := regulator.GetEstimate(); math.Abs(estimate[0]-x[0]) > 1e-6 {
+			t.Errorf("Expected the estimate to track the state, got %v for %v", estimate, x)
+		}
+
+		regulator.Reset()
+		if estimate := regulator.GetObserver().GetEstimate(); estimate[0] != 0 {
+			t.Errorf("Expected a reset estimate, got %v", estimate)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		observer, _ := NewObserver(model, Matrix{{1}, {1}})
+		if _, err := NewRegulator(New(Values{1}), observer); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+
+		twoInputs, _ := NewDiscreteStateSpace(linalg.Identity(2), linalg.Identity(2), Matrix{{1, 0}}, nil, dt)
+		twoInputObserver, _ := NewObserver(twoInputs, Matrix{{1}, {0}})
+		if _, err := NewRegulator(New(Values{1, 1}), twoInputObserver); !errors.Is(err, ErrMultipleInputs) {
+			t.Errorf("Expected ErrMultipleInputs, got %v", err)
+		}
+
+		regulator, _ := NewRegulator(New(Values{1, 1}), observer)
+		if _, err := regulator.Calculate(Values{1, 0}, Values{0, 0}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+		if _, err := regulator.Calculate(Values{1}, Values{0}); !errors.Is(err, ErrSlicessMustBeSameLength) {
+			t.Errorf("Expected ErrSlicessMustBeSameLength, got %v", err)
+		}
+	})
+}
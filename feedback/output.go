@@ -0,0 +1,125 @@
+package feedback
+
+import (
+	"math"
+	"slices"
+)
+
+// VoltageSource returns the present supply voltage, such as a battery measurement.
+type VoltageSource func() float64
+
+// Option configures the output stage of a FullStateFeedback or MultiInputFeedback controller.
+type Option func(*outputStage)
+
+// outputStage applies voltage compensation and saturation to the raw feedback output.
+type outputStage struct {
+	min            Values
+	max            Values
+	nominalVoltage float64
+	voltage        VoltageSource
+	saturated      bool
+	err            error
+}
+
+// newOutputStage creates an unlimited output stage for the given number of inputs and applies
+// the options to it.
+func newOutputStage(inputs int, opts []Option) outputStage {
+	stage := outputStage{
+		min: make(Values, inputs),
+		max: make(Values, inputs),
+	}
+	for i := range inputs {
+		stage.min[i] = math.Inf(-1)
+		stage.max[i] = math.Inf(1)
+	}
+	for _, opt := range opts {
+		opt(&stage)
+	}
+	return stage
+}
+
+// WithOutputLimits sets the same minimum and maximum output for every input. Limits with the
+// minimum above the maximum are rejected by the constructors.
+func WithOutputLimits(min, max float64) Option {
+	return func(s *outputStage) {
+		if min > max {
+			s.err = ErrInvalidLimits
+			return
+		}
+		for i := range s.min {
+			s.min[i] = min
+			s.max[i] = max
+		}
+	}
+}
+
+// WithInputLimits sets a separate minimum and maximum output for each input, for example when
+// the two axes of a gimbal have different motors. Limits of the wrong length, or with a minimum
+// above its maximum, are rejected by the constructors.
+func WithInputLimits(min, max Values) Option {
+	return func(s *outputStage) {
+		if err := s.setLimits(min, max); err != nil {
+			s.err = err
+		}
+	}
+}
+
+// WithVoltageCompensation scales the output by nominal / voltage(), so that an output computed
+// in volts for the nominal supply produces the same effect as the battery sags. The limits apply
+// after compensation. Readings that are not positive disable compensation for that call.
+func WithVoltageCompensation(nominal float64, voltage VoltageSource) Option {
+	return func(s *outputStage) {
+		if nominal <= 0 || voltage == nil {
+			return
+		}
+		s.nominalVoltage = nominal
+		s.voltage = voltage
+	}
+}
+
+// setLimits sets the per-input limits after validating them.
+func (s *outputStage) setLimits(min, max Values) error {
+	if len(min) != len(s.min) || len(max) != len(s.max) {
+		return ErrDimensionMismatch
+	}
+	for i := range min {
+		if min[i] > max[i] {
+			return ErrInvalidLimits
+		}
+	}
+	copy(s.min, min)
+	copy(s.max, max)
+	return nil
+}
+
+// limits returns copies of the per-input limits.
+func (s *outputStage) limits() (min, max Values) {
+	return slices.Clone(s.min), slices.Clone(s.max)
+}
+
+// begin starts a new calculation, clearing the saturation flag and returning the voltage
+// compensation factor.
+func (s *outputStage) begin() float64 {
+	s.saturated = false
+	if s.voltage == nil {
+		return 1
+	}
+	v := s.voltage()
+	if v <= 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 1
+	}
+	return s.nominalVoltage / v
+}
+
+// limit clamps the output of input i to its limits, recording saturation.
+func (s *outputStage) limit(i int, u float64) float64 {
+	switch {
+	case u > s.max[i]:
+		s.saturated = true
+		return s.max[i]
+	case u < s.min[i]:
+		s.saturated = true
+		return s.min[i]
+	}
+	return u
+}
@@ -0,0 +1,180 @@
+package feedback
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOutputLimits(t *testing.T) {
+	t.Run("Single input", func(t *testing.T) {
+		controller := New(Values{2.0, 0.5}, WithOutputLimits(-1.0, 1.0))
+		tests := []struct {
+			name        string
+			setpoint    Values
+			expected    float64
+			isSaturated bool
+		}{
+			{"Within limits", Values{0.25, 0}, 0.5, false},
+			{"Above maximum", Values{10, 0}, 1.0, true},
+			{"Below minimum", Values{-10, 0}, -1.0, true},
+			{"At the limit", Values{0.5, 0}, 1.0, false},
+		}
+		for _, tt := range tests {
+			output, err := controller.Calculate(tt.setpoint, Values{0, 0})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if output != tt.expected || controller.IsSaturated() != tt.isSaturated {
+				t.Errorf("%s: got %f (saturated %v), expected %f (saturated %v)",
+					tt.name, output, controller.IsSaturated(), tt.expected, tt.isSaturated)
+			}
+		}
+
+		if err := controller.SetOutputLimits(-2, 2); err != nil {
+			t.Fatal(err)
+		}
+		if min, max := controller.GetOutputLimits(); min != -2 || max != 2 {
+			t.Errorf("Expected limits [-2, 2], got [%f, %f]", min, max)
+		}
+		if err := controller.SetOutputLimits(3, -3); !errors.Is(err, ErrInvalidLimits) {
+			t.Errorf("Expected ErrInvalidLimits, got %v", err)
+		}
+		if min, max := controller.GetOutputLimits(); min != -2 || max != 2 {
+			t.Errorf("Expected invalid limits to be ignored, got [%f, %f]", min, max)
+		}
+
+		// Without options the output is unbounded
+		unlimited := New(Values{2.0})
+		if output, _ := unlimited.Calculate(Values{1e6}, Values{0}); output != 2e6 || unlimited.IsSaturated() {
+			t.Errorf("Expected an unlimited output, got %f", output)
+		}
+
+		// New validates the options like NewFullState, panicking instead of returning the error
+		func() {
+			defer func() {
+				if err, ok := recover().(error); !ok || !errors.Is(err, ErrInvalidLimits) {
+					t.Errorf("Expected New to panic with ErrInvalidLimits, got %v", err)
+				}
+			}()
+			New(Values{2.0}, WithOutputLimits(1, -1))
+		}()
+	})
+
+	t.Run("Validated constructor", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			opts     []Option
+			expected error
+		}{
+			{"Valid limits", []Option{WithOutputLimits(-1, 1)}, nil},
+			{"Inverted limits", []Option{WithOutputLimits(1, -1)}, ErrInvalidLimits},
+			{"Inverted input limits", []Option{WithInputLimits(Values{1}, Values{-1})}, ErrInvalidLimits},
+			{"Wrong limit length", []Option{WithInputLimits(Values{-1, -1}, Values{1, 1})}, ErrDimensionMismatch},
+		}
+		for _, tt := range tests {
+			controller, err := NewFullState(Values{2.0}, tt.opts...)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+			}
+			if err == nil && controller == nil {
+				t.Errorf("%s: expected a controller", tt.name)
+			}
+		}
+
+		controller, _ := NewFullState(Values{2.0}, WithOutputLimits(-1, 1))
+		if output, _ := controller.Calculate(Values{10}, Values{0}); output != 1 || !controller.IsSaturated() {
+			t.Errorf("Expected a saturated output of 1, got %f", output)
+		}
+	})
+
+	t.Run("Per input", func(t *testing.T) {
+		controller, err := NewMultiInput(Matrix{{1, 0}, {0, 1}}, WithInputLimits(Values{-1, -5}, Values{1, 5}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		output, _ := controller.Calculate(Values{3, 3}, Values{0, 0})
+		if output[0] != 1 || output[1] != 3 || !controller.IsSaturated() {
+			t.Errorf("Expected [1, 3] and saturation, got %v (saturated %v)", output, controller.IsSaturated())
+		}
+		output, _ = controller.Calculate(Values{0.5, 3}, Values{0, 0})
+		if output[0] != 0.5 || controller.IsSaturated() {
+			t.Errorf("Expected no saturation, got %v", output)
+		}
+
+		if err := controller.SetInputLimits(Values{-2, -2}, Values{2, 2}); err != nil {
+			t.Fatal(err)
+		}
+		if min, max := controller.GetInputLimits(); min[1] != -2 || max[1] != 2 {
+			t.Errorf("Unexpected limits %v, %v", min, max)
+		}
+
+		shared, _ := NewMultiInput(Matrix{{1, 0}, {0, 1}}, WithOutputLimits(-1, 1))
+		if output, _ := shared.Calculate(Values{3, -3}, Values{0, 0}); output[0] != 1 || output[1] != -1 {
+			t.Errorf("Expected [1, -1], got %v", output)
+		}
+	})
+
+	t.Run("Voltage compensation", func(t *testing.T) {
+		voltage := 12.0
+		source := func() float64 { return voltage }
+		controller := New(Values{1.0}, WithVoltageCompensation(12, source), WithOutputLimits(-12, 12))
+
+		if output, _ := controller.Calculate(Values{6}, Values{0}); output != 6 {
+			t.Errorf("Expected 6 at nominal voltage, got %f", output)
+		}
+
+		// A sagging battery needs a larger command for the same effect
+		voltage = 10
+		if output, _ := controller.Calculate(Values{6}, Values{0}); !almostEqual(output, 7.2, 1e-12) {
+			t.Errorf("Expected 7.2 at 10 V, got %f", output)
+		}
+		if output, _ := controller.Calculate(Values{11}, Values{0}); output != 12 || !controller.IsSaturated() {
+			t.Errorf("Expected the compensated output to saturate, got %f", output)
+		}
+
+		// Invalid readings leave the output uncompensated
+		voltage = 0
+		if output, _ := controller.Calculate(Values{6}, Values{0}); output != 6 {
+			t.Errorf("Expected 6 without a valid reading, got %f", output)
+		}
+
+		multi, _ := NewMultiInput(Matrix{{1, 0}, {0, 2}}, WithVoltageCompensation(12, func() float64 { return 6 }))
+		if output, _ := multi.Calculate(Values{1, 1}, Values{0, 0}); output[0] != 2 || output[1] != 4 {
+			t.Errorf("Expected [2, 4], got %v", output)
+		}
+	})
+
+	t.Run("Integral feedback shares the output stage", func(t *testing.T) {
+		controller, err := NewIntegralFeedback(Matrix{{1, 0}}, Matrix{{1}}, Matrix{{1, 0}}, 0.1, WithOutputLimits(-1, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range 10 {
+			output, _ := controller.Calculate(Values{5, 0}, Values{0, 0})
+			if output[0] != 1 || !controller.IsSaturated() {
+				t.Fatalf("Expected a saturated output of 1, got %f", output[0])
+			}
+		}
+		if integral := controller.GetIntegral(); integral[0] != 0 {
+			t.Errorf("Expected the integrator to be held while saturated, got %f", integral[0])
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		gain := Matrix{{1, 0}, {0, 1}}
+		if _, err := NewMultiInput(gain, WithInputLimits(Values{-1}, Values{1})); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+		if _, err := NewMultiInput(gain, WithInputLimits(Values{1, 1}, Values{-1, 1})); !errors.Is(err, ErrInvalidLimits) {
+			t.Errorf("Expected ErrInvalidLimits, got %v", err)
+		}
+		if _, err := NewMultiInput(gain, WithOutputLimits(1, -1)); !errors.Is(err, ErrInvalidLimits) {
+			t.Errorf("Expected ErrInvalidLimits, got %v", err)
+		}
+
+		controller, _ := NewMultiInput(gain)
+		if err := controller.SetInputLimits(Values{1, 1}, Values{0, 2}); !errors.Is(err, ErrInvalidLimits) {
+			t.Errorf("Expected ErrInvalidLimits, got %v", err)
+		}
+	})
+}
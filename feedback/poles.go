@@ -0,0 +1,294 @@
+package feedback
+
+import (
+	"math"
+	"math/cmplx"
+	"slices"
+
+	"control/linalg"
+)
+
+const (
+	// poleTolerance is the relative distance below which two poles are considered equal and an
+	// imaginary part is considered zero
+	poleTolerance = 1e-9
+
+	// placementSweeps is the number of eigenvector refinement sweeps performed by Place
+	placementSweeps = 20
+)
+
+// Ackermann computes the gain K that places the poles of A - B K for a single-input system,
+// using Ackermann's formula K = [0 ... 0 1] C^-1 phi(A), where C is the controllability matrix
+// and phi the desired characteristic polynomial.
+//
+// Poles are in the s-plane for a continuous-time system and in the z-plane for a discrete-time
+// system, and complex poles must be accompanied by their conjugates. The returned gain can be
+// passed to New, which applies u = K (setpoint - measurement).
+//
+// Returns ErrDimensionMismatch if the matrix sizes are inconsistent, ErrMultipleInputs if B has
+// more than one column, ErrInvalidPoles if the poles do not form a real polynomial of the right
+// degree and ErrUncontrollable if the system is not controllable.
+func Ackermann(a, b Matrix, poles []complex128) (Values, error) {
+	n, m, err := validatePlacement(a, b, poles)
+	if err != nil {
+		return nil, err
+	}
+	if m != 1 {
+		return nil, ErrMultipleInputs
+	}
+	ordered, err := conjugatePairs(poles)
+	if err != nil {
+		return nil, err
+	}
+	if !controllable(a, b) {
+		return nil, ErrUncontrollable
+	}
+
+	// phi(A) by Horner's method
+	phi := linalg.New(n, n)
+	identity := linalg.Identity(n)
+	for _, c := range characteristicPolynomial(ordered) {
+		phi = phi.Mul(a).Add(identity.Scale(c))
+	}
+
+	// K = w^T phi(A) with C^T w = e_n
+	last := linalg.New(n, 1)
+	last[n-1][0] = 1
+	w, err := controllabilityMatrix(a, b).Transpose().Solve(last)
+	if err != nil {
+		return nil, ErrUncontrollable
+	}
+	gain := make(Values, n)
+	for i := range n {
+		for j := range n {
+			gain[j] += w[i][0] * phi[i][j]
+		}
+	}
+	return gain, nil
+}
+
+// Place computes the gain K that places the poles of A - B K, with one row per input and one
+// column per state.
+//
+// Single-input systems use Ackermann's formula. With several inputs the gain is not unique, and
+// the remaining freedom is used to make the closed-loop eigenvectors as close to orthogonal as
+// possible, following Kautsky, Nichols and Van Dooren. Well conditioned eigenvectors keep the
+// poles near their targets when the model is inaccurate. A pole may be repeated at most as many
+// times as there are inputs.
+//
+// The gain can be passed to NewMultiInput, or for a single input its only row to New. Returns
+// the errors of Ackermann, ErrSingularMatrix if the columns of B are linearly dependent and
+// ErrPoleMultiplicity if a pole is repeated too often.
+func Place(a, b Matrix, poles []complex128) (Matrix, error) {
+	n, m, err := validatePlacement(a, b, poles)
+	if err != nil {
+		return nil, err
+	}
+	if m == 1 {
+		gain, err := Ackermann(a, b, poles)
+		if err != nil {
+			return nil, err
+		}
+		return Matrix{gain}, nil
+	}
+	ordered, err := conjugatePairs(poles)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range ordered {
+		repeats := 0
+		for _, other := range ordered {
+			if cmplx.Abs(p-other) <= poleTolerance*max(1, cmplx.Abs(p)) {
+				repeats++
+			}
+		}
+		if repeats > m {
+			return nil, ErrPoleMultiplicity
+		}
+	}
+	if !controllable(a, b) {
+		return nil, ErrUncontrollable
+	}
+
+	// U1 spans the orthogonal complement of the range of B
+	u1 := linalg.OrthogonalComplement(b.ComplexColumns(), n)
+	if len(u1) != n-m {
+		return nil, ErrSingularMatrix
+	}
+
+	// The eigenvector for pole p must lie in the null space of U1^T (A - p I)
+	subspaces := make([][][]complex128, n)
+	for j, p := range ordered {
+		rows := make([][]complex128, len(u1))
+		for r, u := range u1 {
+			row := make([]complex128, n)
+			for c := range n {
+				for k := range n {
+					row[c] += u[k] * complex(a[k][c], 0)
+				}
+				row[c] -= u[c] * p
+			}
+			// The null space is the orthogonal complement of the conjugated rows
+			for c := range row {
+				row[c] = cmplx.Conj(row[c])
+			}
+			rows[r] = row
+		}
+		subspaces[j] = linalg.OrthogonalComplement(rows, n)
+		if len(subspaces[j]) != m {
+			return nil, ErrUncontrollable
+		}
+	}
+
+	// Repeatedly replace each eigenvector by the direction in its subspace that is most orthogonal
+	// to all the others
+	vectors := startVectors(ordered, subspaces)
+	others := make([][]complex128, 0, n-1)
+	for range placementSweeps {
+		for j := range ordered {
+			if imag(ordered[j]) < 0 {
+				continue
+			}
+			others = others[:0]
+			for i, v := range vectors {
+				if i != j {
+					others = append(others, v)
+				}
+			}
+			y := linalg.OrthogonalComplement(others, n)[0]
+
+			projected := make([]complex128, n)
+			for _, s := range subspaces[j] {
+				c := linalg.InnerProduct(s, y)
+				for i := range projected {
+					projected[i] += c * s[i]
+				}
+			}
+			if norm := linalg.VectorNorm(projected); norm > 1e-12 {
+				for i := range projected {
+					projected[i] /= complex(norm, 0)
+				}
+				vectors[j] = projected
+			}
+			if imag(ordered[j]) > 0 {
+				vectors[j+1] = linalg.Conjugate(vectors[j])
+			}
+		}
+	}
+
+	// The closed loop is M = X diag(poles) X^-1, found by solving X^T M^T = (X diag(poles))^T
+	lhs := make([][]complex128, n)
+	rhs := make([][]complex128, n)
+	for j, v := range vectors {
+		lhs[j] = slices.Clone(v)
+		rhs[j] = make([]complex128, n)
+		for i := range v {
+			rhs[j][i] = ordered[j] * v[i]
+		}
+	}
+	transposed, err := linalg.SolveComplex(lhs, rhs)
+	if err != nil {
+		return nil, ErrPoleMultiplicity
+	}
+	closed := linalg.New(n, n)
+	for i := range n {
+		for j := range n {
+			closed[i][j] = real(transposed[j][i])
+		}
+	}
+
+	// A - B K = M, so K = (B^T B)^-1 B^T (A - M)
+	bt := b.Transpose()
+	return bt.Mul(b).Solve(bt.Mul(a.Sub(closed)))
+}
+
+// startVectors returns the initial eigenvectors for Place, one basis vector of each pole's
+// subspace. The copies of a repeated pole share a subspace, so each copy starts from a different
+// basis vector to give them independent eigenvectors. Conjugate poles get conjugate vectors.
+func startVectors(ordered []complex128, subspaces [][][]complex128) [][]complex128 {
+	vectors := make([][]complex128, len(ordered))
+	for j, p := range ordered {
+		if imag(p) < 0 {
+			vectors[j] = linalg.Conjugate(vectors[j-1])
+			continue
+		}
+		copies := 0
+		for _, other := range ordered[:j] {
+			if cmplx.Abs(p-other) <= poleTolerance*max(1, cmplx.Abs(p)) {
+				copies++
+			}
+		}
+		vectors[j] = slices.Clone(subspaces[j][copies%len(subspaces[j])])
+	}
+	return vectors
+}
+
+// validatePlacement checks that A is n by n, B is n by m and that there are n poles, and
+// returns n and m.
+func validatePlacement(a, b Matrix, poles []complex128) (n, m int, err error) {
+	n, cols := a.Dims()
+	if n == 0 || cols != n {
+		return 0, 0, ErrDimensionMismatch
+	}
+	rows, m := b.Dims()
+	if rows != n || m <= 0 {
+		return 0, 0, ErrDimensionMismatch
+	}
+	if len(poles) != n {
+		return 0, 0, ErrInvalidPoles
+	}
+	return n, m, nil
+}
+
+// conjugatePairs returns the poles ordered so that every complex pole with a positive imaginary
+// part is immediately followed by its exact conjugate, and real poles have no imaginary part.
+// Returns ErrInvalidPoles if a complex pole has no conjugate.
+func conjugatePairs(poles []complex128) ([]complex128, error) {
+	ordered := make([]complex128, 0, len(poles))
+	used := make([]bool, len(poles))
+	for i, p := range poles {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+
+		tolerance := poleTolerance * max(1, cmplx.Abs(p))
+		if math.Abs(imag(p)) <= tolerance {
+			ordered = append(ordered, complex(real(p), 0))
+			continue
+		}
+		partner := -1
+		for j := i + 1; j < len(poles); j++ {
+			if !used[j] && cmplx.Abs(poles[j]-cmplx.Conj(p)) <= tolerance {
+				partner = j
+				break
+			}
+		}
+		if partner < 0 {
+			return nil, ErrInvalidPoles
+		}
+		used[partner] = true
+		upper := complex(real(p), math.Abs(imag(p)))
+		ordered = append(ordered, upper, cmplx.Conj(upper))
+	}
+	return ordered, nil
+}
+
+// characteristicPolynomial returns the coefficients of prod(s - p), highest power first. The
+// poles must come in conjugate pairs so that the coefficients are real.
+func characteristicPolynomial(poles []complex128) []float64 {
+	coefficients := []complex128{1}
+	for _, p := range poles {
+		next := make([]complex128, len(coefficients)+1)
+		for i, c := range coefficients {
+			next[i] += c
+			next[i+1] -= c * p
+		}
+		coefficients = next
+	}
+	result := make([]float64, len(coefficients))
+	for i, c := range coefficients {
+		result[i] = real(c)
+	}
+	return result
+}
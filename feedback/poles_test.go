@@ -0,0 +1,240 @@
+package feedback
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"control/linalg"
+)
+
+// closedLoopPolynomial returns the characteristic polynomial of A - B K, highest power first,
+// using the Faddeev-LeVerrier algorithm.
+func closedLoopPolynomial(a, b, k Matrix) []float64 {
+	n, _ := a.Dims()
+	closed := a.Sub(b.Mul(k))
+	coefficients := make([]float64, n+1)
+	coefficients[0] = 1
+	m := linalg.New(n, n)
+	identity := linalg.Identity(n)
+	for i := 1; i <= n; i++ {
+		m = closed.Mul(m).Add(identity.Scale(coefficients[i-1]))
+		product := closed.Mul(m)
+		var trace float64
+		for j := range n {
+			trace += product[j][j]
+		}
+		coefficients[i] = -trace / float64(i)
+	}
+	return coefficients
+}
+
+func assertPoles(t *testing.T, a, b, k Matrix, poles []complex128) {
+	t.Helper()
+	expected := characteristicPolynomial(poles)
+	got := closedLoopPolynomial(a, b, k)
+	for i := range expected {
+		if math.Abs(got[i]-expected[i]) > 1e-6*max(1, math.Abs(expected[i])) {
+			t.Errorf("Coefficient %d = %f, expected %f", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestAckermann(t *testing.T) {
+	t.Run("Double integrator", func(t *testing.T) {
+		// Poles at -1 and -2 give s^2 + 3s + 2, so K = [2, 3]
+		gain, err := Ackermann(Matrix{{0, 1}, {0, 0}}, Matrix{{0}, {1}}, []complex128{-1, -2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !almostEqual(gain[0], 2, 1e-12) || !almostEqual(gain[1], 3, 1e-12) {
+			t.Errorf("Expected [2, 3], got %v", gain)
+		}
+
+		// The gain drives the state to the setpoint through New
+		controller := New(gain)
+		output, _ := controller.Calculate(Values{1, 0}, Values{0, 0})
+		if !almostEqual(output, 2, 1e-12) {
+			t.Errorf("Expected output 2, got %f", output)
+		}
+	})
+
+	t.Run("Cart-pole with complex poles", func(t *testing.T) {
+		a, b := cartPole()
+		poles := []complex128{-2 + 1i, -2 - 1i, -3, -4}
+		gain, err := Ackermann(a, b, poles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPoles(t, a, b, Matrix{gain}, poles)
+	})
+
+	t.Run("Repeated poles", func(t *testing.T) {
+		a, b := cartPole()
+		poles := []complex128{-2, -2, -2, -2}
+		gain, err := Ackermann(a, b, poles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPoles(t, a, b, Matrix{gain}, poles)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		a := Matrix{{0, 1}, {0, 0}}
+		b := Matrix{{0}, {1}}
+		tests := []struct {
+			name     string
+			a, b     Matrix
+			poles    []complex128
+			expected error
+		}{
+			{"Wrong pole count", a, b, []complex128{-1}, ErrInvalidPoles},
+			{"Missing conjugate", a, b, []complex128{-1 + 1i, -1 + 1i}, ErrInvalidPoles},
+			{"Multiple inputs", a, Matrix{{1, 0}, {0, 1}}, []complex128{-1, -2}, ErrMultipleInputs},
+			{"Wrong B rows", a, Matrix{{1}}, []complex128{-1, -2}, ErrDimensionMismatch},
+			{"Uncontrollable", Matrix{{1, 0}, {0, 2}}, Matrix{{1}, {0}}, []complex128{-1, -2}, ErrUncontrollable},
+		}
+		for _, tt := range tests {
+			if _, err := Ackermann(tt.a, tt.b, tt.poles); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+			}
+		}
+	})
+}
+
+func TestPlace(t *testing.T) {
+	// Two-axis gimbal: angle and rate on each axis, with a small cross-coupling
+	a := Matrix{
+		{0, 1, 0, 0},
+		{0, -0.5, 0, 0.1},
+		{0, 0, 0, 1},
+		{0, 0.1, 0, -0.8},
+	}
+	b := Matrix{
+		{0, 0},
+		{2, 0},
+		{0, 0},
+		{0, 1.5},
+	}
+
+	t.Run("Real and complex poles", func(t *testing.T) {
+		for _, poles := range [][]complex128{
+			{-1, -2, -3, -4},
+			{-2 + 2i, -2 - 2i, -3 + 1i, -3 - 1i},
+			{-5, -2 - 1i, -2 + 1i, -1},
+		} {
+			k, err := Place(a, b, poles)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(k) != 2 || len(k[0]) != 4 {
+				t.Fatalf("Expected a 2x4 gain, got %v", k)
+			}
+			assertPoles(t, a, b, k, poles)
+		}
+	})
+
+	t.Run("Repeated poles", func(t *testing.T) {
+		poles := []complex128{-2, -2, -3, -3}
+		k, err := Place(a, b, poles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPoles(t, a, b, k, poles)
+
+		if _, err := Place(a, b, []complex128{-2, -2, -2, -3}); !errors.Is(err, ErrPoleMultiplicity) {
+			t.Errorf("Expected ErrPoleMultiplicity, got %v", err)
+		}
+	})
+
+	t.Run("Repeated poles that are not adjacent", func(t *testing.T) {
+		// The copies of -1 sit at positions 0 and 2, which share a parity with two inputs
+		a := Matrix{{0, 1, 0}, {0, 0, 1}, {1, -2, 0.5}}
+		b := Matrix{{1, 0}, {0, 0}, {0, 1}}
+		poles := []complex128{-1, -2, -1}
+		k, err := Place(a, b, poles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPoles(t, a, b, k, poles)
+
+		// Each copy must start from its own basis vector rather than one chosen by position
+		basis := [][]complex128{{1, 0, 0}, {0, 0, 1}}
+		vectors := startVectors(poles, [][][]complex128{basis, basis, basis})
+		if slices.Equal(vectors[0], vectors[2]) {
+			t.Errorf("Expected independent start vectors for the copies of -1, got %v", vectors)
+		}
+	})
+
+	t.Run("Single input uses Ackermann", func(t *testing.T) {
+		ca, cb := cartPole()
+		poles := []complex128{-1, -2, -3, -4}
+		k, err := Place(ca, cb, poles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gain, _ := Ackermann(ca, cb, poles)
+		for i := range gain {
+			if !almostEqual(k[0][i], gain[i], 1e-9) {
+				t.Errorf("K[%d] = %f, expected %f", i, k[0][i], gain[i])
+			}
+		}
+	})
+
+	t.Run("Discrete controller", func(t *testing.T) {
+		// Place the poles of a discretized system inside the unit circle and simulate it
+		dt := 0.01
+		ad := linalg.Identity(4).Add(a.Scale(dt))
+		bd := b.Scale(dt)
+		k, err := Place(ad, bd, []complex128{0.9, 0.92, 0.95 + 0.02i, 0.95 - 0.02i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		controller, _ := NewMultiInput(k)
+
+		x := Values{0.5, 0, -0.3, 0}
+		for range 500 {
+			u, _ := controller.Calculate(Values{0, 0, 0, 0}, x)
+			next := make(Values, 4)
+			for i := range next {
+				for j := range x {
+					next[i] += ad[i][j] * x[j]
+				}
+				for j := range u {
+					next[i] += bd[i][j] * u[j]
+				}
+			}
+			x = next
+		}
+		for i, v := range x {
+			if math.Abs(v) > 1e-6 {
+				t.Errorf("State %d did not converge: %f", i, v)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := Place(a, Matrix{{0, 0}, {1, 1}, {0, 0}, {0, 0}}, []complex128{-1, -2, -3, -4}); !errors.Is(err, ErrUncontrollable) {
+			t.Errorf("Expected ErrUncontrollable, got %v", err)
+		}
+		uncontrollable := Matrix{{0, 1, 0}, {0, 0, 0}, {0, 0, 1}}
+		if _, err := Place(uncontrollable, Matrix{{0, 0}, {1, 0}, {0, 0}}, []complex128{-1, -2, -3}); !errors.Is(err, ErrUncontrollable) {
+			t.Errorf("Expected ErrUncontrollable, got %v", err)
+		}
+		if _, err := Place(a, b, []complex128{-1, -2, -3 + 1i, -3}); !errors.Is(err, ErrInvalidPoles) {
+			t.Errorf("Expected ErrInvalidPoles, got %v", err)
+		}
+
+		// Ragged matrices are rejected instead of panicking
+		raggedA := Matrix{{0, 1, 0, 0}, {0, -0.5}, {0, 0, 0, 1}, {0, 0.1, 0, -0.8}}
+		raggedB := Matrix{{0, 0}, {2}, {0, 0}, {0, 1.5}}
+		poles := []complex128{-1, -2, -3, -4}
+		if _, err := Place(raggedA, b, poles); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Ragged A: expected ErrDimensionMismatch, got %v", err)
+		}
+		if _, err := Place(a, raggedB, poles); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Ragged B: expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package feedback
+
+import (
+	"math"
+	"slices"
+
+	"control/interplut"
+)
+
+// ScheduledFeedback is full state feedback whose gain depends on an operating point, such as
+// the angle of an arm whose linearization changes with its pose. A gain vector is given for each
+// of several operating points, and every element of the gain is interpolated between them with a
+// monotone cubic Hermite spline, so the gain passes through the designed values and changes
+// smoothly without overshooting them.
+type ScheduledFeedback struct {
+	tables []*interplut.InterpLUT // One spline per state
+	lower  float64                // Smallest operating point
+	upper  float64                // Largest operating point
+	gain   Values                 // Gain interpolated by the last call to Calculate
+	output outputStage
+}
+
+// NewScheduled creates a new ScheduledFeedback controller from the gain vectors designed at the
+// given operating points, in any order, and the options. The gains are copied.
+//
+// Returns ErrInvalidSchedule if there are fewer than two operating points or they are not
+// distinct and finite, ErrDimensionMismatch if there is not one gain vector per operating point
+// or the gains have different lengths, and ErrInvalidLimits if a minimum exceeds its maximum.
+func NewScheduled(points Values, gains []Values, opts ...Option) (*ScheduledFeedback, error) {
+	if len(points) < 2 {
+		return nil, ErrInvalidSchedule
+	}
+	for _, p := range points {
+		if math.IsNaN(p) || math.IsInf(p, 0) {
+			return nil, ErrInvalidSchedule
+		}
+	}
+	if len(gains) != len(points) {
+		return nil, ErrDimensionMismatch
+	}
+	states := len(gains[0])
+	if states == 0 {
+		return nil, ErrDimensionMismatch
+	}
+	for _, gain := range gains {
+		if len(gain) != states {
+			return nil, ErrDimensionMismatch
+		}
+	}
+
+	tables := make([]*interplut.InterpLUT, states)
+	for i := range tables {
+		tables[i] = interplut.New()
+		for k, p := range points {
+			tables[i].Add(p, gains[k][i])
+		}
+		if err := tables[i].CreateLUT(); err != nil {
+			return nil, ErrInvalidSchedule
+		}
+	}
+
+	output := newOutputStage(1, opts)
+	if output.err != nil {
+		return nil, output.err
+	}
+	return &ScheduledFeedback{
+		tables: tables,
+		lower:  slices.Min(points),
+		upper:  slices.Max(points),
+		gain:   make(Values, states),
+		output: output,
+	}, nil
+}
+
+// Calculate computes the control output with the gain interpolated at the operating point,
+// applying voltage compensation and the output limits. Operating points outside the schedule
+// use the gain of the nearest end.
+//
+// Returns ErrInvalidOperatingPoint if the operating point is NaN and ErrSlicessMustBeSameLength
+// if the setpoint, measurement and gain have different lengths.
+func (sf *ScheduledFeedback) Calculate(operatingPoint float64, setpoint, measurement Values) (float64, error) {
+	if err := sf.interpolate(operatingPoint); err != nil {
+		return 0, err
+	}
+	scale := sf.output.begin()
+	u, err := errorProduct(sf.gain, setpoint, measurement)
+	if err != nil {
+		return 0, err
+	}
+	return sf.output.limit(0, u*scale), nil
+}
+
+// GetGain returns a copy of the gain interpolated at the operating point, clamped to the
+// schedule like in Calculate.
+//
+// Returns ErrInvalidOperatingPoint if the operating point is NaN.
+func (sf *ScheduledFeedback) GetGain(operatingPoint float64) (Values, error) {
+	if err := sf.interpolate(operatingPoint); err != nil {
+		return nil, err
+	}
+	return slices.Clone(sf.gain), nil
+}
+
+// GetSchedule returns the range of operating points covered by the schedule.
+func (sf *ScheduledFeedback) GetSchedule() (lower, upper float64) {
+	return sf.lower, sf.upper
+}
+
+// SetOutputLimits sets the minimum and maximum output values.
+//
+// Returns ErrInvalidLimits and keeps the previous limits if the minimum exceeds the maximum.
+func (sf *ScheduledFeedback) SetOutputLimits(min, max float64) error {
+	return sf.output.setLimits(Values{min}, Values{max})
+}
+
+// GetOutputLimits returns the current output limits
+func (sf *ScheduledFeedback) GetOutputLimits() (min, max float64) {
+	return sf.output.min[0], sf.output.max[0]
+}
+
+// IsSaturated reports whether the output of the last call to Calculate was limited.
+func (sf *ScheduledFeedback) IsSaturated() bool {
+	return sf.output.saturated
+}
+
+// interpolate writes the gain at the operating point, clamped to the schedule, into sf.gain
+// without allocating.
+func (sf *ScheduledFeedback) interpolate(operatingPoint float64) error {
+	if math.IsNaN(operatingPoint) {
+		return ErrInvalidOperatingPoint
+	}
+	operatingPoint = min(max(operatingPoint, sf.lower), sf.upper)
+	for i, table := range sf.tables {
+		sf.gain[i], _ = table.Get(operatingPoint)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package feedback
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// armSchedule returns gains for an arm at three angles, stiffer where gravity acts hardest.
+func armSchedule() (points Values, gains []Values) {
+	return Values{math.Pi / 2, 0, -math.Pi / 2},
+		[]Values{{4, 0.5}, {10, 1.5}, {4, 0.5}}
+}
+
+func TestScheduledFeedback(t *testing.T) {
+	points, gains := armSchedule()
+	controller, err := NewScheduled(points, gains)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Passes through the designed gains", func(t *testing.T) {
+		for k, p := range points {
+			gain, err := controller.GetGain(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range gain {
+				if math.Abs(gain[i]-gains[k][i]) > 1e-12 {
+					t.Errorf("K(%f)[%d] = %f, expected %f", p, i, gain[i], gains[k][i])
+				}
+			}
+		}
+	})
+
+	t.Run("Interpolates smoothly", func(t *testing.T) {
+		previous, _ := controller.GetGain(-math.Pi / 2)
+		for angle := -math.Pi / 2; angle <= 0; angle += 0.01 {
+			gain, _ := controller.GetGain(angle)
+			// Monotone between the points, never leaving the range of the designed gains
+			if gain[0] < previous[0]-1e-12 || gain[0] < 4 || gain[0] > 10 {
+				t.Fatalf("K(%f)[0] = %f is not monotone within [4, 10]", angle, gain[0])
+			}
+			if math.Abs(gain[0]-previous[0]) > 0.1 {
+				t.Fatalf("K(%f)[0] jumps from %f to %f", angle, previous[0], gain[0])
+			}
+			previous = gain
+		}
+	})
+
+	t.Run("Two points are linear", func(t *testing.T) {
+		linear, _ := NewScheduled(Values{0, 1}, []Values{{1, 2}, {3, 4}})
+		gain, _ := linear.GetGain(0.25)
+		if math.Abs(gain[0]-1.5) > 1e-12 || math.Abs(gain[1]-2.5) > 1e-12 {
+			t.Errorf("Expected [1.5 2.5], got %v", gain)
+		}
+	})
+
+	t.Run("Clamps outside the schedule", func(t *testing.T) {
+		gain, err := controller.GetGain(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gain[0] != 4 || gain[1] != 0.5 {
+			t.Errorf("Expected the gain at the upper end, got %v", gain)
+		}
+		if lower, upper := controller.GetSchedule(); lower != -math.Pi/2 || upper != math.Pi/2 {
+			t.Errorf("Expected the schedule [-pi/2, pi/2], got [%f, %f]", lower, upper)
+		}
+	})
+
+	t.Run("Calculate", func(t *testing.T) {
+		setpoint := Values{1, 0}
+		measurement := Values{0.5, 0.2}
+		u, err := controller.Calculate(0, setpoint, measurement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := 10*0.5 + 1.5*-0.2
+		if math.Abs(u-expected) > 1e-12 {
+			t.Errorf("Expected %f, got %f", expected, u)
+		}
+
+		if _, err := controller.Calculate(math.NaN(), setpoint, measurement); !errors.Is(err, ErrInvalidOperatingPoint) {
+			t.Errorf("Expected ErrInvalidOperatingPoint, got %v", err)
+		}
+		if _, err := controller.Calculate(0, Values{1}, Values{0}); !errors.Is(err, ErrSlicessMustBeSameLength) {
+			t.Errorf("Expected ErrSlicessMustBeSameLength, got %v", err)
+		}
+	})
+
+	t.Run("Output limits", func(t *testing.T) {
+		limited, err := NewScheduled(points, gains, WithOutputLimits(-1, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, _ := limited.Calculate(0, Values{1, 0}, Values{0, 0})
+		if u != 1 || !limited.IsSaturated() {
+			t.Errorf("Expected a saturated output of 1, got %f", u)
+		}
+		if err := limited.SetOutputLimits(-20, 20); err != nil {
+			t.Fatal(err)
+		}
+		if min, max := limited.GetOutputLimits(); min != -20 || max != 20 {
+			t.Errorf("Expected limits [-20, 20], got [%f, %f]", min, max)
+		}
+		if err := limited.SetOutputLimits(1, -1); !errors.Is(err, ErrInvalidLimits) {
+			t.Errorf("Expected ErrInvalidLimits, got %v", err)
+		}
+		u, _ = limited.Calculate(0, Values{1, 0}, Values{0, 0})
+		if u != 10 || limited.IsSaturated() {
+			t.Errorf("Expected an unsaturated output of 10, got %f", u)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			points   Values
+			gains    []Values
+			opts     []Option
+			expected error
+		}{
+			{"One point", Values{0}, []Values{{1}}, nil, ErrInvalidSchedule},
+			{"Duplicate points", Values{0, 0}, []Values{{1}, {2}}, nil, ErrInvalidSchedule},
+			{"NaN point", Values{0, math.NaN()}, []Values{{1}, {2}}, nil, ErrInvalidSchedule},
+			{"Missing gain", Values{0, 1}, []Values{{1}}, nil, ErrDimensionMismatch},
+			{"Ragged gains", Values{0, 1}, []Values{{1}, {1, 2}}, nil, ErrDimensionMismatch},
+			{"Empty gain", Values{0, 1}, []Values{{}, {}}, nil, ErrDimensionMismatch},
+			{"Inverted limits", Values{0, 1}, []Values{{1}, {2}}, []Option{WithOutputLimits(1, -1)}, ErrInvalidLimits},
+		}
+		for _, tt := range tests {
+			if _, err := NewScheduled(tt.points, tt.gains, tt.opts...); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,311 @@
+package feedback
+
+import (
+	"math"
+	"math/cmplx"
+
+	"control/linalg"
+)
+
+// DiscretizationMethod selects how a continuous-time model is converted to discrete time.
+type DiscretizationMethod int
+
+const (
+	// ZeroOrderHold is exact for inputs that are held constant over each sample period, which is
+	// how most digital controllers drive their actuators.
+	ZeroOrderHold DiscretizationMethod = iota
+	// Tustin integrates the states with the trapezoidal rule, holding the input over each
+	// sample like ZeroOrderHold. Like the bilinear transform it preserves stability and distorts
+	// the frequency response less than forward Euler, and the states and outputs remain the
+	// physical ones, so measured states can be fed to observers and controllers.
+	Tustin
+	// ForwardEuler uses x[k+1] = x[k] + dt (A x[k] + B u[k]). It is the cheapest and least
+	// accurate method, and can turn a stable model unstable if the sample time is too long.
+	ForwardEuler
+)
+
+// StateSpace is a linear time-invariant plant model
+//
+//	dx/dt = A x + B u, y = C x + D u    (continuous time)
+//	x[k+1] = A x[k] + B u[k], y[k] = C x[k] + D u[k]    (discrete time)
+//
+// with n states, m inputs and p outputs. A discrete-time model has a positive sample time.
+type StateSpace struct {
+	a, b, c, d Matrix
+	sampleTime float64
+}
+
+// NewStateSpace creates a continuous-time model. A nil C means that every state is measured,
+// and a nil D means that the inputs do not feed through to the outputs. The matrices are copied.
+//
+// Returns ErrDimensionMismatch if A is not square or the other matrices do not match it.
+func NewStateSpace(a, b, c, d Matrix) (*StateSpace, error) {
+	n, cols := a.Dims()
+	if n == 0 || cols != n {
+		return nil, ErrDimensionMismatch
+	}
+	rows, m := b.Dims()
+	if rows != n || m <= 0 {
+		return nil, ErrDimensionMismatch
+	}
+	if c == nil {
+		c = linalg.Identity(n)
+	}
+	p, cols := c.Dims()
+	if p == 0 || cols != n {
+		return nil, ErrDimensionMismatch
+	}
+	if d == nil {
+		d = linalg.New(p, m)
+	}
+	if rows, cols := d.Dims(); rows != p || cols != m {
+		return nil, ErrDimensionMismatch
+	}
+	return &StateSpace{
+		a: a.Clone(),
+		b: b.Clone(),
+		c: c.Clone(),
+		d: d.Clone(),
+	}, nil
+}
+
+// NewDiscreteStateSpace creates a discrete-time model with the given sample time in seconds.
+//
+// Returns ErrInvalidSampleTime if the sample time is not positive, or any error from
+// NewStateSpace.
+func NewDiscreteStateSpace(a, b, c, d Matrix, sampleTime float64) (*StateSpace, error) {
+	if sampleTime <= 0 || math.IsInf(sampleTime, 0) || math.IsNaN(sampleTime) {
+		return nil, ErrInvalidSampleTime
+	}
+	ss, err := NewStateSpace(a, b, c, d)
+	if err != nil {
+		return nil, err
+	}
+	ss.sampleTime = sampleTime
+	return ss, nil
+}
+
+// Matrices returns copies of the A, B, C and D matrices.
+func (ss *StateSpace) Matrices() (a, b, c, d Matrix) {
+	return ss.a.Clone(), ss.b.Clone(), ss.c.Clone(), ss.d.Clone()
+}
+
+// States returns the number of states.
+func (ss *StateSpace) States() int {
+	return len(ss.a)
+}
+
+// Inputs returns the number of inputs.
+func (ss *StateSpace) Inputs() int {
+	return len(ss.b[0])
+}
+
+// Outputs returns the number of outputs.
+func (ss *StateSpace) Outputs() int {
+	return len(ss.c)
+}
+
+// IsDiscrete reports whether the model is in discrete time.
+func (ss *StateSpace) IsDiscrete() bool {
+	return ss.sampleTime > 0
+}
+
+// GetSampleTime returns the sample time in seconds, or 0 for a continuous-time model.
+func (ss *StateSpace) GetSampleTime() float64 {
+	return ss.sampleTime
+}
+
+// Discretize converts a continuous-time model to discrete time with the given sample time.
+//
+// Returns ErrNotContinuous if the model is already discrete, ErrInvalidSampleTime if the sample
+// time is not positive and ErrSingularMatrix if the Tustin transform is undefined because
+// 2/dt is an eigenvalue of A.
+func (ss *StateSpace) Discretize(dt float64, method DiscretizationMethod) (*StateSpace, error) {
+	if ss.IsDiscrete() {
+		return nil, ErrNotContinuous
+	}
+	if dt <= 0 || math.IsInf(dt, 0) || math.IsNaN(dt) {
+		return nil, ErrInvalidSampleTime
+	}
+	n, m := ss.States(), ss.Inputs()
+	identity := linalg.Identity(n)
+
+	var ad, bd, cd, dd Matrix
+	switch method {
+	case ZeroOrderHold:
+		// exp([A B; 0 0] dt) = [Ad Bd; 0 I]
+		block := linalg.New(n+m, n+m)
+		for i := range n {
+			for j := range n {
+				block[i][j] = ss.a[i][j] * dt
+			}
+			for j := range m {
+				block[i][n+j] = ss.b[i][j] * dt
+			}
+		}
+		e, err := block.Exp()
+		if err != nil {
+			return nil, err
+		}
+		ad, bd = linalg.New(n, n), linalg.New(n, m)
+		for i := range n {
+			copy(ad[i], e[i][:n])
+			copy(bd[i], e[i][n:])
+		}
+		cd, dd = ss.c.Clone(), ss.d.Clone()
+	case Tustin:
+		// (I - A dt/2) x[k+1] = (I + A dt/2) x[k] + B dt u[k], so with M = (I - A dt/2)^-1,
+		// Ad = M (I + A dt/2) and Bd = M B dt. C and D are unchanged, so y = C x + D u holds for
+		// the physical state, unlike the realization with Cd = C M that matches the bilinear
+		// transfer function at the cost of a transformed state.
+		half := ss.a.Scale(dt / 2)
+		inverse, err := identity.Sub(half).Inverse()
+		if err != nil {
+			return nil, err
+		}
+		ad = inverse.Mul(identity.Add(half))
+		bd = inverse.Mul(ss.b).Scale(dt)
+		cd, dd = ss.c.Clone(), ss.d.Clone()
+	case ForwardEuler:
+		ad = identity.Add(ss.a.Scale(dt))
+		bd = ss.b.Scale(dt)
+		cd, dd = ss.c.Clone(), ss.d.Clone()
+	default:
+		return nil, ErrInvalidMethod
+	}
+	return &StateSpace{a: ad, b: bd, c: cd, d: dd, sampleTime: dt}, nil
+}
+
+// Step advances a discrete-time model by one sample, returning the next state
+// x[k+1] = A x[k] + B u[k].
+//
+// Returns ErrNotDiscrete for a continuous-time model and ErrDimensionMismatch if the state or
+// input has the wrong length.
+func (ss *StateSpace) Step(x, u Values) (Values, error) {
+	if !ss.IsDiscrete() {
+		return nil, ErrNotDiscrete
+	}
+	return ss.propagate(x, u)
+}
+
+// Derivative returns the state derivative dx/dt = A x + B u of a continuous-time model.
+//
+// Returns ErrNotContinuous for a discrete-time model and ErrDimensionMismatch if the state or
+// input has the wrong length.
+func (ss *StateSpace) Derivative(x, u Values) (Values, error) {
+	if ss.IsDiscrete() {
+		return nil, ErrNotContinuous
+	}
+	return ss.propagate(x, u)
+}
+
+// Output returns the output y = C x + D u.
+//
+// Returns ErrDimensionMismatch if the state or input has the wrong length.
+func (ss *StateSpace) Output(x, u Values) (Values, error) {
+	if len(x) != ss.States() || len(u) != ss.Inputs() {
+		return nil, ErrDimensionMismatch
+	}
+	y := make(Values, ss.Outputs())
+	ss.outputInto(y, x, u)
+	return y, nil
+}
+
+// Simulate runs a discrete-time model from the initial state x0 with one input vector per
+// sample, and returns the outputs y[0] to y[len(inputs)-1] together with the final state.
+//
+// Returns ErrNotDiscrete for a continuous-time model and ErrDimensionMismatch if a vector has the
+// wrong length.
+func (ss *StateSpace) Simulate(x0 Values, inputs []Values) (outputs []Values, final Values, err error) {
+	if !ss.IsDiscrete() {
+		return nil, nil, ErrNotDiscrete
+	}
+	x := x0
+	outputs = make([]Values, len(inputs))
+	for k, u := range inputs {
+		if outputs[k], err = ss.Output(x, u); err != nil {
+			return nil, nil, err
+		}
+		if x, err = ss.propagate(x, u); err != nil {
+			return nil, nil, err
+		}
+	}
+	return outputs, append(Values(nil), x...), nil
+}
+
+// propagate returns A x + B u.
+func (ss *StateSpace) propagate(x, u Values) (Values, error) {
+	if len(x) != ss.States() || len(u) != ss.Inputs() {
+		return nil, ErrDimensionMismatch
+	}
+	result := make(Values, ss.States())
+	ss.propagateInto(result, x, u)
+	return result, nil
+}
+
+// propagateInto writes A x + B u into dst without checking dimensions.
+func (ss *StateSpace) propagateInto(dst, x, u Values) {
+	for i := range dst {
+		sum := 0.0
+		for j, v := range x {
+			sum += ss.a[i][j] * v
+		}
+		for j, v := range u {
+			sum += ss.b[i][j] * v
+		}
+		dst[i] = sum
+	}
+}
+
+// outputInto writes C x + D u into dst without checking dimensions.
+func (ss *StateSpace) outputInto(dst, x, u Values) {
+	for i := range dst {
+		sum := 0.0
+		for j, v := range x {
+			sum += ss.c[i][j] * v
+		}
+		for j, v := range u {
+			sum += ss.d[i][j] * v
+		}
+		dst[i] = sum
+	}
+}
+
+// IsControllable reports whether every state can be driven by the inputs, that is whether the
+// controllability matrix [B, A B, ..., A^(n-1) B] has full rank.
+func (ss *StateSpace) IsControllable() bool {
+	return controllable(ss.a, ss.b)
+}
+
+// IsObservable reports whether the states can be reconstructed from the outputs, that is
+// whether the observability matrix [C; C A; ...; C A^(n-1)] has full rank.
+func (ss *StateSpace) IsObservable() bool {
+	return controllable(ss.a.Transpose(), ss.c.Transpose())
+}
+
+// Poles returns the eigenvalues of A. Complex poles are returned as adjacent conjugate pairs.
+//
+// Returns ErrNoConvergence if the eigenvalue iteration fails.
+func (ss *StateSpace) Poles() ([]complex128, error) {
+	return ss.a.Eigenvalues()
+}
+
+// IsStable reports whether the model is asymptotically stable: every pole has a negative real
+// part in continuous time, or lies strictly inside the unit circle in discrete time.
+//
+// Returns ErrNoConvergence if the eigenvalue iteration fails.
+func (ss *StateSpace) IsStable() (bool, error) {
+	poles, err := ss.Poles()
+	if err != nil {
+		return false, err
+	}
+	for _, p := range poles {
+		if ss.IsDiscrete() && cmplx.Abs(p) >= 1 {
+			return false, nil
+		}
+		if !ss.IsDiscrete() && real(p) >= 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,300 @@
+package feedback
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"math/cmplx"
+	"slices"
+	"testing"
+
+	"control/linalg"
+)
+
+func assertMatrix(t *testing.T, name string, got, expected Matrix, tolerance float64) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("%s: expected %d rows, got %d", name, len(expected), len(got))
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(got[i][j]-expected[i][j]) > tolerance {
+				t.Errorf("%s[%d][%d] = %f, expected %f", name, i, j, got[i][j], expected[i][j])
+			}
+		}
+	}
+}
+
+// sortPoles orders poles by real and then imaginary part, treating real parts that differ only
+// by rounding as equal.
+func sortPoles(poles []complex128) {
+	slices.SortFunc(poles, func(a, b complex128) int {
+		if math.Abs(real(a)-real(b)) > 1e-6 {
+			return cmp.Compare(real(a), real(b))
+		}
+		return cmp.Compare(imag(a), imag(b))
+	})
+}
+
+func TestStateSpaceDiscretize(t *testing.T) {
+	dt := 0.1
+
+	t.Run("Zero-order hold double integrator", func(t *testing.T) {
+		ss, _ := NewStateSpace(Matrix{{0, 1}, {0, 0}}, Matrix{{0}, {1}}, nil, nil)
+		discrete, err := ss.Discretize(dt, ZeroOrderHold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, b, c, d := discrete.Matrices()
+		assertMatrix(t, "A", a, Matrix{{1, dt}, {0, 1}}, 1e-14)
+		assertMatrix(t, "B", b, Matrix{{dt * dt / 2}, {dt}}, 1e-14)
+		assertMatrix(t, "C", c, linalg.Identity(2), 0)
+		assertMatrix(t, "D", d, Matrix{{0}, {0}}, 0)
+		if !discrete.IsDiscrete() || discrete.GetSampleTime() != dt {
+			t.Errorf("Expected a discrete model with sample time %f", dt)
+		}
+	})
+
+	t.Run("First-order lag", func(t *testing.T) {
+		ss, _ := NewStateSpace(Matrix{{-2}}, Matrix{{1}}, Matrix{{2}}, nil)
+		decay := math.Exp(-2 * dt)
+
+		zoh, _ := ss.Discretize(dt, ZeroOrderHold)
+		a, b, _, _ := zoh.Matrices()
+		assertMatrix(t, "ZOH A", a, Matrix{{decay}}, 1e-14)
+		assertMatrix(t, "ZOH B", b, Matrix{{(1 - decay) / 2}}, 1e-14)
+
+		tustin, _ := ss.Discretize(dt, Tustin)
+		a, _, _, _ = tustin.Matrices()
+		assertMatrix(t, "Tustin A", a, Matrix{{(1 - dt) / (1 + dt)}}, 1e-14)
+
+		euler, _ := ss.Discretize(dt, ForwardEuler)
+		a, b, _, _ = euler.Matrices()
+		assertMatrix(t, "Euler A", a, Matrix{{1 - 2*dt}}, 1e-14)
+		assertMatrix(t, "Euler B", b, Matrix{{dt}}, 1e-14)
+
+		// Every method preserves the DC gain of C (-A)^-1 B + D = 1
+		for name, model := range map[string]*StateSpace{"ZOH": zoh, "Tustin": tustin, "Euler": euler} {
+			x := Values{0}
+			var y Values
+			for range 1000 {
+				y, _ = model.Output(x, Values{1})
+				x, _ = model.Step(x, Values{1})
+			}
+			if !almostEqual(y[0], 1, 1e-9) {
+				t.Errorf("%s: expected DC gain 1, got %f", name, y[0])
+			}
+		}
+	})
+
+	t.Run("Tustin outputs are the physical states", func(t *testing.T) {
+		// A double integrator driven by u = 1 for 1 s reaches position 0.5 and velocity 1
+		ss, _ := NewStateSpace(Matrix{{0, 1}, {0, 0}}, Matrix{{0}, {1}}, nil, nil)
+		tustin, _ := ss.Discretize(0.1, Tustin)
+		x := Values{0, 0}
+		for range 10 {
+			x, _ = tustin.Step(x, Values{1})
+		}
+		y, _ := tustin.Output(x, Values{1})
+		for i, expected := range []float64{0.5, 1} {
+			if !almostEqual(x[i], expected, 1e-12) || !almostEqual(y[i], expected, 1e-12) {
+				t.Errorf("Element %d: expected state and output %f, got %f and %f", i, expected, x[i], y[i])
+			}
+		}
+	})
+
+	t.Run("Step response matches the continuous model", func(t *testing.T) {
+		ss, _ := NewStateSpace(Matrix{{-2}}, Matrix{{1}}, Matrix{{2}}, nil)
+		discrete, _ := ss.Discretize(dt, ZeroOrderHold)
+
+		inputs := make([]Values, 20)
+		for k := range inputs {
+			inputs[k] = Values{1}
+		}
+		outputs, final, err := discrete.Simulate(Values{0}, inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, y := range outputs {
+			expected := 1 - math.Exp(-2*float64(k)*dt)
+			if !almostEqual(y[0], expected, 1e-12) {
+				t.Errorf("Sample %d: expected %f, got %f", k, expected, y[0])
+			}
+		}
+		if expected := (1 - math.Exp(-2*20*dt)) / 2; !almostEqual(final[0], expected, 1e-12) {
+			t.Errorf("Expected final state %f, got %f", expected, final[0])
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		ss, _ := NewStateSpace(Matrix{{0, 1}, {0, 0}}, Matrix{{0}, {1}}, nil, nil)
+		if _, err := ss.Discretize(0, ZeroOrderHold); !errors.Is(err, ErrInvalidSampleTime) {
+			t.Errorf("Expected ErrInvalidSampleTime, got %v", err)
+		}
+		if _, err := ss.Discretize(dt, DiscretizationMethod(42)); !errors.Is(err, ErrInvalidMethod) {
+			t.Errorf("Expected ErrInvalidMethod, got %v", err)
+		}
+		if _, err := ss.Step(Values{0, 0}, Values{0}); !errors.Is(err, ErrNotDiscrete) {
+			t.Errorf("Expected ErrNotDiscrete, got %v", err)
+		}
+
+		discrete, _ := ss.Discretize(dt, ZeroOrderHold)
+		if _, err := discrete.Discretize(dt, ZeroOrderHold); !errors.Is(err, ErrNotContinuous) {
+			t.Errorf("Expected ErrNotContinuous, got %v", err)
+		}
+		if _, err := discrete.Derivative(Values{0, 0}, Values{0}); !errors.Is(err, ErrNotContinuous) {
+			t.Errorf("Expected ErrNotContinuous, got %v", err)
+		}
+		if _, err := discrete.Step(Values{0}, Values{0}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+		if _, _, err := discrete.Simulate(Values{0, 0}, []Values{{0, 0}}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+
+		// 2/dt is an eigenvalue of A, so the bilinear transform is undefined
+		unstable, _ := NewStateSpace(Matrix{{20}}, Matrix{{1}}, nil, nil)
+		if _, err := unstable.Discretize(dt, Tustin); !errors.Is(err, ErrSingularMatrix) {
+			t.Errorf("Expected ErrSingularMatrix, got %v", err)
+		}
+	})
+}
+
+func TestNewStateSpace(t *testing.T) {
+	a := Matrix{{0, 1}, {0, 0}}
+	b := Matrix{{0}, {1}}
+
+	ss, err := NewStateSpace(a, b, Matrix{{1, 0}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ss.States() != 2 || ss.Inputs() != 1 || ss.Outputs() != 1 || ss.IsDiscrete() {
+		t.Errorf("Unexpected model shape: %d states, %d inputs, %d outputs", ss.States(), ss.Inputs(), ss.Outputs())
+	}
+
+	// The model keeps its own copy of the matrices
+	a[0][1] = 5
+	if got, _ := ss.Derivative(Values{0, 1}, Values{2}); got[0] != 1 || got[1] != 2 {
+		t.Errorf("Expected derivative [1, 2], got %v", got)
+	}
+
+	tests := []struct {
+		name       string
+		a, b, c, d Matrix
+	}{
+		{"Non-square A", Matrix{{0, 1}}, b, nil, nil},
+		{"Wrong B rows", a, Matrix{{1}}, nil, nil},
+		{"Wrong C columns", a, b, Matrix{{1}}, nil},
+		{"Wrong D size", a, b, nil, Matrix{{0, 0}}},
+	}
+	for _, tt := range tests {
+		if _, err := NewStateSpace(tt.a, tt.b, tt.c, tt.d); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("%s: expected ErrDimensionMismatch, got %v", tt.name, err)
+		}
+	}
+	if _, err := NewDiscreteStateSpace(a, b, nil, nil, -1); !errors.Is(err, ErrInvalidSampleTime) {
+		t.Errorf("Expected ErrInvalidSampleTime, got %v", err)
+	}
+}
+
+func TestStateSpaceAnalysis(t *testing.T) {
+	t.Run("Controllability and observability", func(t *testing.T) {
+		a := Matrix{{0, 1}, {0, 0}}
+		position, _ := NewStateSpace(a, Matrix{{0}, {1}}, Matrix{{1, 0}}, nil)
+		if !position.IsControllable() || !position.IsObservable() {
+			t.Error("Expected a controllable and observable double integrator")
+		}
+
+		// Velocity alone says nothing about position, and a force on position is not enough
+		velocity, _ := NewStateSpace(a, Matrix{{1}, {0}}, Matrix{{0, 1}}, nil)
+		if velocity.IsControllable() || velocity.IsObservable() {
+			t.Error("Expected an uncontrollable and unobservable model")
+		}
+	})
+
+	t.Run("Poles", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			a        Matrix
+			expected []complex128
+		}{
+			{"Real", Matrix{{0, 1}, {-2, -3}}, []complex128{-2, -1}},
+			{"Oscillator", Matrix{{0, 1}, {-1, 0}}, []complex128{-1i, 1i}},
+			{"Triangular", Matrix{{3, 7, -1}, {0, -1, 2}, {0, 0, 0.5}}, []complex128{-1, 0.5, 3}},
+			{
+				// Companion matrix of (s + 1)(s + 2)(s^2 + 2s + 5)(s - 3)
+				"Companion",
+				Matrix{
+					{0, 1, 0, 0, 0},
+					{0, 0, 1, 0, 0},
+					{0, 0, 0, 1, 0},
+					{0, 0, 0, 0, 1},
+					{30, 47, 20, 2, -2},
+				},
+				[]complex128{-2, -1 - 2i, -1, -1 + 2i, 3},
+			},
+		}
+		for _, tt := range tests {
+			ss, _ := NewStateSpace(tt.a, linalg.New(len(tt.a), 1), nil, nil)
+			poles, err := ss.Poles()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sortPoles(poles)
+			for i := range tt.expected {
+				if cmplx.Abs(poles[i]-tt.expected[i]) > 1e-9 {
+					t.Errorf("%s: pole %d = %v, expected %v", tt.name, i, poles[i], tt.expected[i])
+				}
+			}
+		}
+	})
+
+	t.Run("Placed poles", func(t *testing.T) {
+		a, b := cartPole()
+		target := []complex128{-4, -3, -2 - 1i, -2 + 1i}
+		gain, _ := Ackermann(a, b, target)
+		closed, _ := NewStateSpace(a.Sub(b.Mul(Matrix{gain})), b, nil, nil)
+		poles, err := closed.Poles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sortPoles(poles)
+		for i := range target {
+			if cmplx.Abs(poles[i]-target[i]) > 1e-6 {
+				t.Errorf("Pole %d = %v, expected %v", i, poles[i], target[i])
+			}
+		}
+	})
+
+	t.Run("Stability", func(t *testing.T) {
+		a, b := cartPole()
+		open, _ := NewStateSpace(a, b, nil, nil)
+		if stable, _ := open.IsStable(); stable {
+			t.Error("Expected the open-loop cart-pole to be unstable")
+		}
+
+		gain, _ := LQR(a, b, linalg.Diagonal(1, 0, 1, 0), Matrix{{1}})
+		closed, _ := NewStateSpace(a.Sub(b.Mul(gain)), b, nil, nil)
+		if stable, _ := closed.IsStable(); !stable {
+			t.Error("Expected the LQR closed loop to be stable")
+		}
+
+		// Forward Euler with a long sample time destabilizes a stable model
+		lag, _ := NewStateSpace(Matrix{{-10}}, Matrix{{1}}, nil, nil)
+		for _, tt := range []struct {
+			dt     float64
+			method DiscretizationMethod
+			stable bool
+		}{
+			{0.1, ForwardEuler, true},
+			{0.3, ForwardEuler, false},
+			{0.3, ZeroOrderHold, true},
+			{0.3, Tustin, true},
+		} {
+			discrete, _ := lag.Discretize(tt.dt, tt.method)
+			if stable, _ := discrete.IsStable(); stable != tt.stable {
+				t.Errorf("dt %f, method %d: expected stable %v", tt.dt, tt.method, tt.stable)
+			}
+		}
+	})
+}
@@ -0,0 +1,296 @@
+package filter
+
+import (
+	"errors"
+	"math"
+)
+
+// AlphaBetaFilter implements an alpha-beta tracking filter.
+//
+// The filter assumes a constant-velocity model. Each step it predicts the position from the
+// previous estimate, then corrects the position by alpha and the velocity by beta/dt times the
+// residual. It is the steady-state form of a two-state Kalman filter and is a lightweight way
+// to estimate velocity from position measurements such as encoder ticks.
+type AlphaBetaFilter struct {
+	alpha       float64 // Position correction gain
+	beta        float64 // Velocity correction gain
+	dt          float64 // Nominal time between measurements
+	position    float64 // Position estimate
+	velocity    float64 // Velocity estimate
+	initialized bool    // Whether the filter has been initialized
+}
+
+// NewAlphaBetaFilter creates a new alpha-beta filter.
+//
+// Parameters:
+//   - alpha: Position correction gain (0 < alpha < 2)
+//   - beta: Velocity correction gain (0 < beta < 4 - 2*alpha)
+//   - dt: Nominal time between measurements in seconds, used by Estimate
+//
+// Returns an error if the gains are outside the stable region or dt is not positive.
+func NewAlphaBetaFilter(alpha, beta, dt float64) (*AlphaBetaFilter, error) {
+	if alpha <= 0 || alpha >= 2 {
+		return nil, errors.New("alpha must be between 0 and 2 (exclusive)")
+	}
+	if beta <= 0 || beta >= 4-2*alpha {
+		return nil, errors.New("beta must be between 0 and 4 - 2*alpha (exclusive)")
+	}
+	if dt <= 0 {
+		return nil, errors.New("dt must be positive")
+	}
+
+	return &AlphaBetaFilter{
+		alpha: alpha,
+		beta:  beta,
+		dt:    dt,
+	}, nil
+}
+
+// AlphaBetaFromTrackingIndex returns the optimal alpha and beta gains for the given tracking index.
+//
+// The tracking index is lambda = sigmaW * dt^2 / sigmaV, where sigmaW is the standard deviation
+// of the target's acceleration and sigmaV is the standard deviation of the measurement noise.
+// Larger values track maneuvers more closely; smaller values smooth more heavily.
+func AlphaBetaFromTrackingIndex(lambda float64) (alpha, beta float64) {
+	lambda = math.Abs(lambda)
+	r := (4 + lambda - math.Sqrt(8*lambda+lambda*lambda)) / 4
+	alpha = 1 - r*r
+	beta = 2*(2-alpha) - 4*math.Sqrt(1-alpha)
+	return alpha, beta
+}
+
+// Estimate processes a position measurement taken dt after the previous one and returns the
+// position estimate.
+// This implements the Filter interface.
+//
+// On the first call, the filter is initialized with the measurement and zero velocity.
+func (abf *AlphaBetaFilter) Estimate(measurement float64) float64 {
+	return abf.EstimateWithDt(measurement, abf.dt)
+}
+
+// EstimateWithDt processes a position measurement taken dt seconds after the previous one and
+// returns the position estimate. This is useful when measurements arrive at irregular intervals.
+// A non-positive dt only applies the position correction.
+func (abf *AlphaBetaFilter) EstimateWithDt(measurement, dt float64) float64 {
+	if !abf.initialized {
+		abf.position = measurement
+		abf.velocity = 0.0
+		abf.initialized = true
+		return measurement
+	}
+
+	// Predict
+	if dt > 0 {
+		abf.position += abf.velocity * dt
+	}
+
+	// Correct
+	residual := measurement - abf.position
+	abf.position += abf.alpha * residual
+	if dt > 0 {
+		abf.velocity += abf.beta * residual / dt
+	}
+
+	return abf.position
+}
+
+// GetPosition returns the current position estimate.
+func (abf *AlphaBetaFilter) GetPosition() float64 {
+	return abf.position
+}
+
+// GetVelocity returns the current velocity estimate.
+func (abf *AlphaBetaFilter) GetVelocity() float64 {
+	return abf.velocity
+}
+
+// SetState sets the position and velocity estimates and marks the filter as initialized.
+func (abf *AlphaBetaFilter) SetState(position, velocity float64) {
+	abf.position = position
+	abf.velocity = velocity
+	abf.initialized = true
+}
+
+// GetAlpha returns the position correction gain.
+func (abf *AlphaBetaFilter) GetAlpha() float64 {
+	return abf.alpha
+}
+
+// GetBeta returns the velocity correction gain.
+func (abf *AlphaBetaFilter) GetBeta() float64 {
+	return abf.beta
+}
+
+// GetGain returns the position correction gain (alias for GetAlpha).
+// This method exists to satisfy the Filter interface.
+func (abf *AlphaBetaFilter) GetGain() float64 {
+	return abf.alpha
+}
+
+// Reset resets the filter to its uninitialized state.
+// The next call to Estimate will initialize the filter with the provided measurement.
+func (abf *AlphaBetaFilter) Reset() {
+	abf.position = 0.0
+	abf.velocity = 0.0
+	abf.initialized = false
+}
+
+// IsInitialized returns whether the filter has processed at least one measurement.
+func (abf *AlphaBetaFilter) IsInitialized() bool {
+	return abf.initialized
+}
+
+// AlphaBetaGammaFilter implements an alpha-beta-gamma tracking filter.
+//
+// The filter extends the AlphaBetaFilter with a constant-acceleration model, estimating
+// position, velocity and acceleration. The acceleration is corrected by 2*gamma/dt^2 times
+// the residual.
+type AlphaBetaGammaFilter struct {
+	alpha        float64 // Position correction gain
+	beta         float64 // Velocity correction gain
+	gamma        float64 // Acceleration correction gain
+	dt           float64 // Nominal time between measurements
+	position     float64 // Position estimate
+	velocity     float64 // Velocity estimate
+	acceleration float64 // Acceleration estimate
+	initialized  bool    // Whether the filter has been initialized
+}
+
+// NewAlphaBetaGammaFilter creates a new alpha-beta-gamma filter.
+//
+// Parameters:
+//   - alpha: Position correction gain (0 < alpha < 2)
+//   - beta: Velocity correction gain (0 < beta < 4 - 2*alpha)
+//   - gamma: Acceleration correction gain (gamma > 0)
+//   - dt: Nominal time between measurements in seconds, used by Estimate
+//
+// Returns an error if the gains are outside the stable region or dt is not positive.
+func NewAlphaBetaGammaFilter(alpha, beta, gamma, dt float64) (*AlphaBetaGammaFilter, error) {
+	if alpha <= 0 || alpha >= 2 {
+		return nil, errors.New("alpha must be between 0 and 2 (exclusive)")
+	}
+	if beta <= 0 || beta >= 4-2*alpha {
+		return nil, errors.New("beta must be between 0 and 4 - 2*alpha (exclusive)")
+	}
+	if gamma <= 0 {
+		return nil, errors.New("gamma must be positive")
+	}
+	if dt <= 0 {
+		return nil, errors.New("dt must be positive")
+	}
+
+	return &AlphaBetaGammaFilter{
+		alpha: alpha,
+		beta:  beta,
+		gamma: gamma,
+		dt:    dt,
+	}, nil
+}
+
+// AlphaBetaGammaFromDamping returns critically damped (fading-memory) alpha, beta and gamma
+// gains for the given damping parameter theta (0 < theta < 1).
+//
+// Theta close to 1 gives heavy smoothing and slow response; theta close to 0 follows the
+// measurements closely.
+func AlphaBetaGammaFromDamping(theta float64) (alpha, beta, gamma float64) {
+	alpha = 1 - theta*theta*theta
+	beta = 1.5 * (1 - theta) * (1 - theta) * (1 + theta)
+	gamma = 0.5 * (1 - theta) * (1 - theta) * (1 - theta)
+	return alpha, beta, gamma
+}
+
+// Estimate processes a position measurement taken dt after the previous one and returns the
+// position estimate.
+// This implements the Filter interface.
+//
+// On the first call, the filter is initialized with the measurement and zero velocity and acceleration.
+func (abgf *AlphaBetaGammaFilter) Estimate(measurement float64) float64 {
+	return abgf.EstimateWithDt(measurement, abgf.dt)
+}
+
+// EstimateWithDt processes a position measurement taken dt seconds after the previous one and
+// returns the position estimate. This is useful when measurements arrive at irregular intervals.
+// A non-positive dt only applies the position correction.
+func (abgf *AlphaBetaGammaFilter) EstimateWithDt(measurement, dt float64) float64 {
+	if !abgf.initialized {
+		abgf.position = measurement
+		abgf.velocity = 0.0
+		abgf.acceleration = 0.0
+		abgf.initialized = true
+		return measurement
+	}
+
+	// Predict
+	if dt > 0 {
+		abgf.position += abgf.velocity*dt + 0.5*abgf.acceleration*dt*dt
+		abgf.velocity += abgf.acceleration * dt
+	}
+
+	// Correct
+	residual := measurement - abgf.position
+	abgf.position += abgf.alpha * residual
+	if dt > 0 {
+		abgf.velocity += abgf.beta * residual / dt
+		abgf.acceleration += 2 * abgf.gamma * residual / (dt * dt)
+	}
+
+	return abgf.position
+}
+
+// GetPosition returns the current position estimate.
+func (abgf *AlphaBetaGammaFilter) GetPosition() float64 {
+	return abgf.position
+}
+
+// GetVelocity returns the current velocity estimate.
+func (abgf *AlphaBetaGammaFilter) GetVelocity() float64 {
+	return abgf.velocity
+}
+
+// GetAcceleration returns the current acceleration estimate.
+func (abgf *AlphaBetaGammaFilter) GetAcceleration() float64 {
+	return abgf.acceleration
+}
+
+// SetState sets the position, velocity and acceleration estimates and marks the filter as initialized.
+func (abgf *AlphaBetaGammaFilter) SetState(position, velocity, acceleration float64) {
+	abgf.position = position
+	abgf.velocity = velocity
+	abgf.acceleration = acceleration
+	abgf.initialized = true
+}
+
+// GetAlpha returns the position correction gain.
+func (abgf *AlphaBetaGammaFilter) GetAlpha() float64 {
+	return abgf.alpha
+}
+
+// GetBeta returns the velocity correction gain.
+func (abgf *AlphaBetaGammaFilter) GetBeta() float64 {
+	return abgf.beta
+}
+
+// GetGamma returns the acceleration correction gain.
+func (abgf *AlphaBetaGammaFilter) GetGamma() float64 {
+	return abgf.gamma
+}
+
+// GetGain returns the position correction gain (alias for GetAlpha).
+// This method exists to satisfy the Filter interface.
+func (abgf *AlphaBetaGammaFilter) GetGain() float64 {
+	return abgf.alpha
+}
+
+// Reset resets the filter to its uninitialized state.
+// The next call to Estimate will initialize the filter with the provided measurement.
+func (abgf *AlphaBetaGammaFilter) Reset() {
+	abgf.position = 0.0
+	abgf.velocity = 0.0
+	abgf.acceleration = 0.0
+	abgf.initialized = false
+}
+
+// IsInitialized returns whether the filter has processed at least one measurement.
+func (abgf *AlphaBetaGammaFilter) IsInitialized() bool {
+	return abgf.initialized
+}
@@ -0,0 +1,162 @@
+package filter
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestAlphaBetaFilter tests the AlphaBetaFilter functionality
+func TestAlphaBetaFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		tests := []struct {
+			name            string
+			alpha, beta, dt float64
+		}{
+			{"Zero alpha", 0.0, 0.1, 0.01},
+			{"Alpha too large", 2.0, 0.1, 0.01},
+			{"Zero beta", 0.5, 0.0, 0.01},
+			{"Beta too large", 0.5, 3.0, 0.01},
+			{"Zero dt", 0.5, 0.1, 0.0},
+		}
+		for _, tt := range tests {
+			if _, err := NewAlphaBetaFilter(tt.alpha, tt.beta, tt.dt); err == nil {
+				t.Errorf("%s: expected error", tt.name)
+			}
+		}
+
+		abf, err := NewAlphaBetaFilter(0.5, 0.1, 0.01)
+		if err != nil {
+			t.Fatalf("Expected no error for valid parameters, got %v", err)
+		}
+		var _ Filter = abf
+	})
+
+	t.Run("Ramp tracking", func(t *testing.T) {
+		dt := 0.01
+		abf, _ := NewAlphaBetaFilter(0.5, 0.1, dt)
+
+		velocity := 3.0
+		for i := range 500 {
+			abf.Estimate(velocity * float64(i) * dt)
+		}
+
+		if math.Abs(abf.GetVelocity()-velocity) > 1e-6 {
+			t.Errorf("Expected velocity %f, got %f", velocity, abf.GetVelocity())
+		}
+		if math.Abs(abf.GetPosition()-velocity*499*dt) > 1e-6 {
+			t.Errorf("Expected position %f, got %f", velocity*499*dt, abf.GetPosition())
+		}
+	})
+
+	t.Run("Irregular sampling", func(t *testing.T) {
+		abf, _ := NewAlphaBetaFilter(0.5, 0.1, 0.01)
+
+		velocity := -2.0
+		time := 0.0
+		for i := range 1000 {
+			time += 0.005 + 0.01*float64(i%3)
+			abf.EstimateWithDt(velocity*time, 0.005+0.01*float64(i%3))
+		}
+
+		if math.Abs(abf.GetVelocity()-velocity) > 1e-3 {
+			t.Errorf("Expected velocity %f, got %f", velocity, abf.GetVelocity())
+		}
+	})
+
+	t.Run("Reset functionality", func(t *testing.T) {
+		abf, _ := NewAlphaBetaFilter(0.5, 0.1, 0.01)
+		abf.Estimate(1.0)
+		abf.Estimate(2.0)
+
+		abf.Reset()
+		if abf.IsInitialized() || abf.GetVelocity() != 0.0 {
+			t.Error("Expected filter to be uninitialized with zero velocity after reset")
+		}
+		if estimate := abf.Estimate(7.0); estimate != 7.0 {
+			t.Errorf("Expected first estimate after reset 7.0, got %f", estimate)
+		}
+	})
+}
+
+// TestAlphaBetaFromTrackingIndex tests the tracking index gain helper
+func TestAlphaBetaFromTrackingIndex(t *testing.T) {
+	previousAlpha := 0.0
+	for _, lambda := range []float64{0.01, 0.1, 1.0, 10.0} {
+		alpha, beta := AlphaBetaFromTrackingIndex(lambda)
+
+		if alpha <= previousAlpha || alpha >= 1 {
+			t.Errorf("Lambda %f: expected alpha to increase within (0, 1), got %f", lambda, alpha)
+		}
+		if _, err := NewAlphaBetaFilter(alpha, beta, 0.01); err != nil {
+			t.Errorf("Lambda %f: gains (%f, %f) are not stable: %v", lambda, alpha, beta, err)
+		}
+
+		// The optimal gains satisfy lambda^2 = beta^2 / (1 - alpha)
+		if got := beta * beta / (1 - alpha); math.Abs(got-lambda*lambda) > 1e-9 {
+			t.Errorf("Lambda %f: expected beta^2/(1-alpha) = %f, got %f", lambda, lambda*lambda, got)
+		}
+		previousAlpha = alpha
+	}
+}
+
+// TestAlphaBetaGammaFilter tests the AlphaBetaGammaFilter functionality
+func TestAlphaBetaGammaFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewAlphaBetaGammaFilter(0.5, 0.1, 0.0, 0.01); err == nil {
+			t.Error("Expected error for zero gamma")
+		}
+		if _, err := NewAlphaBetaGammaFilter(2.5, 0.1, 0.01, 0.01); err == nil {
+			t.Error("Expected error for alpha out of range")
+		}
+		if _, err := NewAlphaBetaGammaFilter(0.5, 0.1, 0.01, -1.0); err == nil {
+			t.Error("Expected error for negative dt")
+		}
+	})
+
+	t.Run("Parabola tracking", func(t *testing.T) {
+		dt := 0.01
+		alpha, beta, gamma := AlphaBetaGammaFromDamping(0.8)
+		abgf, err := NewAlphaBetaGammaFilter(alpha, beta, gamma, dt)
+		if err != nil {
+			t.Fatalf("Failed to create filter: %v", err)
+		}
+		var _ Filter = abgf
+
+		acceleration := 4.0
+		var time float64
+		for i := range 1000 {
+			time = float64(i) * dt
+			abgf.Estimate(0.5 * acceleration * time * time)
+		}
+
+		if math.Abs(abgf.GetAcceleration()-acceleration) > 1e-6 {
+			t.Errorf("Expected acceleration %f, got %f", acceleration, abgf.GetAcceleration())
+		}
+		if math.Abs(abgf.GetVelocity()-acceleration*time) > 1e-6 {
+			t.Errorf("Expected velocity %f, got %f", acceleration*time, abgf.GetVelocity())
+		}
+	})
+
+	t.Run("Noise reduction", func(t *testing.T) {
+		dt := 0.01
+		alpha, beta, gamma := AlphaBetaGammaFromDamping(0.9)
+		abgf, _ := NewAlphaBetaGammaFilter(alpha, beta, gamma, dt)
+		rng := rand.New(rand.NewSource(7))
+
+		var rawError, filteredError float64
+		for i := range 2000 {
+			truth := math.Sin(float64(i) * dt)
+			measurement := truth + rng.NormFloat64()*0.05
+			estimate := abgf.Estimate(measurement)
+			if i > 100 {
+				rawError += (measurement - truth) * (measurement - truth)
+				filteredError += (estimate - truth) * (estimate - truth)
+			}
+		}
+
+		if filteredError >= rawError {
+			t.Errorf("Expected filtered error %f to be less than raw error %f", filteredError, rawError)
+		}
+	})
+}
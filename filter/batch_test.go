@@ -0,0 +1,176 @@
+package filter
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// batchFilters returns a fresh instance of every filter, of which LowPassFilter and ChainFilter
+// implement BatchFilter and the rest use the fallback of EstimateAll.
+func batchFilters(t testing.TB) map[string]Filter {
+	t.Helper()
+
+	lpf, _ := NewLowPassFilter(0.8)
+	kf, _ := NewKalmanFilter(0.2, 0.5, 5)
+	median, _ := NewMedianFilter(5)
+	maf, _ := NewMovingAverageFilter(8)
+	wmaf, _ := NewLinearWeightedMovingAverageFilter(6)
+	hampel, _ := NewHampelFilter(7, 3)
+	ab, _ := NewAlphaBetaFilter(0.5, 0.1, 0.01)
+	abg, _ := NewAlphaBetaGammaFilter(0.5, 0.1, 0.01, 0.01)
+	sg, _ := NewSavitzkyGolayFilter(9, 2, 0.01)
+	chainLPF, _ := NewLowPassFilter(0.5)
+	chainMedian, _ := NewMedianFilter(3)
+	parallelLPF, _ := NewLowPassFilter(0.9)
+	parallelMAF, _ := NewMovingAverageFilter(4)
+	parallel, err := Parallel([]Filter{parallelLPF, parallelMAF}, []float64{0.3, 0.7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]Filter{
+		"LowPassFilter":               lpf,
+		"KalmanFilter":                kf,
+		"MedianFilter":                median,
+		"MovingAverageFilter":         maf,
+		"WeightedMovingAverageFilter": wmaf,
+		"HampelFilter":                hampel,
+		"AlphaBetaFilter":             ab,
+		"AlphaBetaGammaFilter":        abg,
+		"SavitzkyGolayFilter":         sg,
+		"ChainFilter":                 Chain(chainMedian, chainLPF),
+		"ParallelFilter":              parallel,
+	}
+}
+
+// TestEstimateAll tests that batch estimation matches per-sample estimation
+func TestEstimateAll(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	in := make([]float64, 200)
+	for i := range in {
+		in[i] = math.Sin(float64(i)*0.05) + 0.1*rng.NormFloat64()
+	}
+
+	batch := batchFilters(t)
+	reference := batchFilters(t)
+	for name, f := range batch {
+		// Split the input to check that state carries over between calls
+		out := make([]float64, len(in))
+		EstimateAll(f, in[:50], out[:50])
+		EstimateAll(f, in[50:], out[50:])
+
+		for i, measurement := range in {
+			expected := reference[name].Estimate(measurement)
+			if out[i] != expected {
+				t.Errorf("%s: sample %d: expected %v, got %v", name, i, expected, out[i])
+				break
+			}
+		}
+	}
+
+	t.Run("In place", func(t *testing.T) {
+		for name, f := range batchFilters(t) {
+			expected := make([]float64, len(in))
+			EstimateAll(f, in, expected)
+			f.Reset()
+
+			data := make([]float64, len(in))
+			copy(data, in)
+			EstimateAll(f, data, data)
+			for i := range data {
+				if data[i] != expected[i] {
+					t.Errorf("%s: sample %d: expected %v, got %v", name, i, expected[i], data[i])
+					break
+				}
+			}
+		}
+	})
+
+	t.Run("Batch methods", func(t *testing.T) {
+		lpf, _ := NewLowPassFilter(0.5)
+		median, _ := NewMedianFilter(3)
+		for name, f := range map[string]Filter{"LowPassFilter": lpf, "ChainFilter": Chain(median)} {
+			if _, ok := f.(BatchFilter); !ok {
+				t.Errorf("Expected %s to implement BatchFilter", name)
+			}
+		}
+	})
+
+	t.Run("Fallback for filters without a batch method", func(t *testing.T) {
+		lpf, _ := NewLowPassFilter(0.5)
+		reference, _ := NewLowPassFilter(0.5)
+		f := struct{ Filter }{lpf}
+
+		out := make([]float64, 3)
+		EstimateAll(f, []float64{1, 2, 3}, out)
+		for i, measurement := range []float64{1, 2, 3} {
+			if expected := reference.Estimate(measurement); out[i] != expected {
+				t.Errorf("Sample %d: expected %f, got %f", i, expected, out[i])
+			}
+		}
+	})
+
+	t.Run("Zero allocations", func(t *testing.T) {
+		out := make([]float64, len(in))
+		for name, f := range batchFilters(t) {
+			allocs := testing.AllocsPerRun(20, func() {
+				EstimateAll(f, in, out)
+			})
+			if allocs != 0 {
+				t.Errorf("%s: expected 0 allocations, got %f", name, allocs)
+			}
+		}
+
+		lpf, _ := NewLowPassFilter(0.8)
+		kf, _ := NewKalmanFilter(0.2, 0.5, 5)
+		for name, f := range map[string]Filter{"LowPassFilter": lpf, "KalmanFilter": kf} {
+			allocs := testing.AllocsPerRun(20, func() {
+				FiltFiltInto(f, in, out)
+			})
+			if allocs != 0 {
+				t.Errorf("FiltFiltInto with %s: expected 0 allocations, got %f", name, allocs)
+			}
+		}
+
+		s, _ := NewRTSSmoother(0.01, 1)
+		s.SmoothInto(in, out)
+		if allocs := testing.AllocsPerRun(20, func() { s.SmoothInto(in, out) }); allocs != 0 {
+			t.Errorf("RTSSmoother: expected 0 allocations, got %f", allocs)
+		}
+	})
+}
+
+// BenchmarkLowPassFilterEstimate measures per-sample estimation over a block of samples
+func BenchmarkLowPassFilterEstimate(b *testing.B) {
+	lpf, _ := NewLowPassFilter(0.8)
+	in := make([]float64, 1024)
+	out := make([]float64, len(in))
+	for i := range in {
+		in[i] = float64(i % 17)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, measurement := range in {
+			out[j] = lpf.Estimate(measurement)
+		}
+	}
+}
+
+// BenchmarkLowPassFilterEstimateAll measures batch estimation over a block of samples
+func BenchmarkLowPassFilterEstimateAll(b *testing.B) {
+	lpf, _ := NewLowPassFilter(0.8)
+	in := make([]float64, 1024)
+	out := make([]float64, len(in))
+	for i := range in {
+		in[i] = float64(i % 17)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lpf.EstimateAll(in, out)
+	}
+}
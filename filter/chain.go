@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"errors"
+)
+
+// ChainFilter runs a measurement through a series of filters, feeding the output of each
+// stage into the next. It implements the Filter interface, so a pipeline such as
+// "median then low-pass" can be used anywhere a single filter is accepted.
+type ChainFilter struct {
+	stages []Filter
+}
+
+// Chain creates a filter that applies the given filters in order. Nil filters are ignored.
+// An empty chain passes measurements through unchanged.
+func Chain(filters ...Filter) *ChainFilter {
+	stages := make([]Filter, 0, len(filters))
+	for _, f := range filters {
+		if f != nil {
+			stages = append(stages, f)
+		}
+	}
+
+	return &ChainFilter{
+		stages: stages,
+	}
+}
+
+// Estimate passes the measurement through each stage in order and returns the output of the last stage.
+// This implements the Filter interface.
+func (cf *ChainFilter) Estimate(measurement float64) float64 {
+	estimate := measurement
+	for _, stage := range cf.stages {
+		estimate = stage.Estimate(estimate)
+	}
+	return estimate
+}
+
+// EstimateAll passes all the measurements through each stage in turn, writing the estimates to
+// out, which must be at least as long as in. in and out may be the same slice. The result is
+// the same as calling Estimate for each measurement, but every stage runs over the whole slice
+// at once.
+// This implements the BatchFilter interface.
+func (cf *ChainFilter) EstimateAll(in, out []float64) {
+	out = out[:len(in)]
+	copy(out, in)
+	for _, stage := range cf.stages {
+		EstimateAll(stage, out, out)
+	}
+}
+
+// GetGain returns 0. The stages' gains have different meanings, such as the weight on the
+// previous estimate of a LowPassFilter and the weight on the newest sample of a
+// MovingAverageFilter, so they do not combine into a gain of the chain. Use Stages to inspect
+// the gain of each stage.
+// This method exists to satisfy the Filter interface.
+func (cf *ChainFilter) GetGain() float64 {
+	return 0.0
+}
+
+// Reset resets every stage of the chain.
+func (cf *ChainFilter) Reset() {
+	for _, stage := range cf.stages {
+		stage.Reset()
+	}
+}
+
+// Stages returns a copy of the filters in the chain, in the order they are applied.
+func (cf *ChainFilter) Stages() []Filter {
+	stages := make([]Filter, len(cf.stages))
+	copy(stages, cf.stages)
+	return stages
+}
+
+// ParallelFilter runs the same measurement through several filters and returns the weighted
+// sum of their outputs. It implements the Filter interface.
+type ParallelFilter struct {
+	stages  []Filter
+	weights []float64
+}
+
+// Parallel creates a filter that feeds each measurement to every filter and combines the
+// outputs using the given weights. Weights are normally chosen to sum to 1 so that a constant
+// signal passes through unchanged.
+//
+// Returns an error if no filters are given, the number of weights does not match the number
+// of filters, or any filter is nil.
+func Parallel(filters []Filter, weights []float64) (*ParallelFilter, error) {
+	if len(filters) == 0 {
+		return nil, errors.New("at least one filter is required")
+	}
+	if len(filters) != len(weights) {
+		return nil, errors.New("the number of weights must match the number of filters")
+	}
+	for _, f := range filters {
+		if f == nil {
+			return nil, errors.New("filters must not be nil")
+		}
+	}
+
+	pf := &ParallelFilter{
+		stages:  make([]Filter, len(filters)),
+		weights: make([]float64, len(weights)),
+	}
+	copy(pf.stages, filters)
+	copy(pf.weights, weights)
+
+	return pf, nil
+}
+
+// Complementary creates a filter that blends two filters of the same signal, weighting the
+// output of a by weight and the output of b by 1 - weight.
+//
+// Returns an error if either filter is nil or weight is not in the range [0, 1].
+func Complementary(a, b Filter, weight float64) (*ParallelFilter, error) {
+	if weight < 0 || weight > 1 {
+		return nil, errors.New("weight must be between 0 and 1 (inclusive)")
+	}
+	return Parallel([]Filter{a, b}, []float64{weight, 1 - weight})
+}
+
+// Estimate passes the measurement to every stage and returns the weighted sum of their outputs.
+// This implements the Filter interface.
+func (pf *ParallelFilter) Estimate(measurement float64) float64 {
+	var estimate float64
+	for i, stage := range pf.stages {
+		estimate += pf.weights[i] * stage.Estimate(measurement)
+	}
+	return estimate
+}
+
+// GetGain returns 0, as the gains of the stages have different meanings and do not combine
+// into a gain of the parallel filter. Use Stages to inspect the gain of each stage.
+// This method exists to satisfy the Filter interface.
+func (pf *ParallelFilter) GetGain() float64 {
+	return 0.0
+}
+
+// Reset resets every stage.
+func (pf *ParallelFilter) Reset() {
+	for _, stage := range pf.stages {
+		stage.Reset()
+	}
+}
+
+// Stages returns a copy of the filters that are combined.
+func (pf *ParallelFilter) Stages() []Filter {
+	stages := make([]Filter, len(pf.stages))
+	copy(stages, pf.stages)
+	return stages
+}
+
+// GetWeights returns a copy of the weights applied to each stage.
+func (pf *ParallelFilter) GetWeights() []float64 {
+	weights := make([]float64, len(pf.weights))
+	copy(weights, pf.weights)
+	return weights
+}
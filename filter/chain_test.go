@@ -0,0 +1,133 @@
+package filter
+
+import (
+	"math"
+	"testing"
+)
+
+// TestChainFilter tests the ChainFilter functionality
+func TestChainFilter(t *testing.T) {
+	t.Run("Median then low-pass", func(t *testing.T) {
+		median, _ := NewMedianFilter(3)
+		lowPass, _ := NewLowPassFilter(0.5)
+		chain := Chain(median, lowPass)
+
+		var _ Filter = chain
+
+		measurements := []float64{1.0, 1.0, 100.0, 1.0, 1.0}
+		for i, measurement := range measurements {
+			if estimate := chain.Estimate(measurement); estimate != 1.0 {
+				t.Errorf("Step %d: expected spike to be removed before smoothing, got %f", i, estimate)
+			}
+		}
+	})
+
+	t.Run("Order matters", func(t *testing.T) {
+		median, _ := NewMedianFilter(3)
+		lowPass, _ := NewLowPassFilter(0.5)
+		chain := Chain(lowPass, median)
+
+		var last float64
+		for _, measurement := range []float64{1.0, 1.0, 100.0, 1.0} {
+			last = chain.Estimate(measurement)
+		}
+		if last == 1.0 {
+			t.Error("Expected smoothing the spike first to leave a residue after the median")
+		}
+	})
+
+	t.Run("Empty chain and nil stages", func(t *testing.T) {
+		chain := Chain(nil)
+		if len(chain.Stages()) != 0 {
+			t.Errorf("Expected nil stages to be ignored, got %d stages", len(chain.Stages()))
+		}
+		if estimate := chain.Estimate(4.2); estimate != 4.2 {
+			t.Errorf("Expected empty chain to pass through, got %f", estimate)
+		}
+		if chain.GetGain() != 0.0 {
+			t.Errorf("Expected empty chain gain 0, got %f", chain.GetGain())
+		}
+	})
+
+	t.Run("Gain and reset", func(t *testing.T) {
+		a, _ := NewMovingAverageFilter(2)
+		b, _ := NewLowPassFilter(0.4)
+		chain := Chain(a, b)
+
+		// The stage gains mean different things, so the chain reports none
+		if gain := chain.GetGain(); gain != 0.0 {
+			t.Errorf("Expected gain 0, got %f", gain)
+		}
+
+		chain.Estimate(10.0)
+		chain.Estimate(20.0)
+		chain.Reset()
+
+		if b.IsInitialized() {
+			t.Error("Expected every stage to be reset")
+		}
+		if estimate := chain.Estimate(3.0); estimate != 3.0 {
+			t.Errorf("Expected first estimate after reset 3.0, got %f", estimate)
+		}
+	})
+}
+
+// TestParallelFilter tests the ParallelFilter functionality
+func TestParallelFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		lowPass, _ := NewLowPassFilter(0.5)
+
+		if _, err := Parallel(nil, nil); err == nil {
+			t.Error("Expected error for no filters")
+		}
+		if _, err := Parallel([]Filter{lowPass}, []float64{0.5, 0.5}); err == nil {
+			t.Error("Expected error for mismatched weights")
+		}
+		if _, err := Parallel([]Filter{lowPass, nil}, []float64{0.5, 0.5}); err == nil {
+			t.Error("Expected error for nil filter")
+		}
+		if _, err := Complementary(lowPass, lowPass, 1.5); err == nil {
+			t.Error("Expected error for weight out of range")
+		}
+	})
+
+	t.Run("Weighted combination", func(t *testing.T) {
+		slow, _ := NewLowPassFilter(0.9)
+		fast, _ := NewMovingAverageFilter(1)
+		pf, err := Complementary(slow, fast, 0.25)
+		if err != nil {
+			t.Fatalf("Failed to create complementary filter: %v", err)
+		}
+
+		var _ Filter = pf
+
+		pf.Estimate(0.0)
+		estimate := pf.Estimate(10.0)
+		expected := 0.25*1.0 + 0.75*10.0
+		if math.Abs(estimate-expected) > 1e-12 {
+			t.Errorf("Expected %f, got %f", expected, estimate)
+		}
+
+		if gain := pf.GetGain(); gain != 0.0 {
+			t.Errorf("Expected gain 0, got %f", gain)
+		}
+	})
+
+	t.Run("Constant signal passes through", func(t *testing.T) {
+		a, _ := NewLowPassFilter(0.3)
+		b, _ := NewMedianFilter(5)
+		c, _ := NewMovingAverageFilter(4)
+		pf, _ := Parallel([]Filter{a, b, c}, []float64{0.2, 0.3, 0.5})
+
+		for range 10 {
+			if estimate := pf.Estimate(2.5); math.Abs(estimate-2.5) > 1e-12 {
+				t.Errorf("Expected 2.5, got %f", estimate)
+			}
+		}
+
+		pf.Reset()
+		if a.IsInitialized() {
+			t.Error("Expected every stage to be reset")
+		}
+	})
+}
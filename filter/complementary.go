@@ -0,0 +1,191 @@
+package filter
+
+import (
+	"errors"
+)
+
+// ComplementaryFilter fuses a gyro rate with an accelerometer tilt angle to estimate attitude.
+//
+// The gyro is accurate over short periods but drifts, while the accelerometer angle is
+// drift-free but noisy and disturbed by linear acceleration. The filter integrates the gyro
+// through a high-pass filter and passes the accelerometer angle through a low-pass filter
+// with the same crossover time constant:
+//
+//	angle = alpha * (angle + gyroRate*dt) + (1-alpha) * accelAngle, alpha = tau / (tau + dt)
+//
+// Below the crossover frequency 1/(2*pi*tau) the accelerometer dominates; above it the gyro does.
+type ComplementaryFilter struct {
+	timeConstant float64 // Crossover time constant in seconds
+	angle        float64 // Fused angle estimate
+	initialized  bool    // Whether the filter has been initialized
+}
+
+// NewComplementaryFilter creates a new complementary attitude filter.
+//
+// Parameters:
+//   - timeConstant: Crossover time constant in seconds. Larger values trust the gyro for
+//     longer and reject more accelerometer noise, but correct drift more slowly.
+//
+// Returns an error if the time constant is not positive.
+func NewComplementaryFilter(timeConstant float64) (*ComplementaryFilter, error) {
+	if timeConstant <= 0 {
+		return nil, errors.New("time constant must be positive")
+	}
+
+	return &ComplementaryFilter{
+		timeConstant: timeConstant,
+	}, nil
+}
+
+// Update fuses a gyro rate and an accelerometer angle measured dt seconds after the previous
+// update and returns the fused angle. The angle and rate must use consistent units
+// (e.g. radians and radians per second).
+//
+// On the first call, the filter is initialized with the accelerometer angle.
+func (cf *ComplementaryFilter) Update(gyroRate, accelAngle, dt float64) float64 {
+	if !cf.initialized {
+		cf.angle = accelAngle
+		cf.initialized = true
+		return cf.angle
+	}
+	if dt <= 0 {
+		return cf.angle
+	}
+
+	alpha := cf.timeConstant / (cf.timeConstant + dt)
+	cf.angle = alpha*(cf.angle+gyroRate*dt) + (1-alpha)*accelAngle
+	return cf.angle
+}
+
+// GetAngle returns the current fused angle.
+func (cf *ComplementaryFilter) GetAngle() float64 {
+	return cf.angle
+}
+
+// SetAngle sets the fused angle and marks the filter as initialized.
+func (cf *ComplementaryFilter) SetAngle(angle float64) {
+	cf.angle = angle
+	cf.initialized = true
+}
+
+// GetTimeConstant returns the crossover time constant in seconds.
+func (cf *ComplementaryFilter) GetTimeConstant() float64 {
+	return cf.timeConstant
+}
+
+// Reset resets the filter to its uninitialized state.
+// The next call to Update will initialize the filter with the accelerometer angle.
+func (cf *ComplementaryFilter) Reset() {
+	cf.angle = 0.0
+	cf.initialized = false
+}
+
+// IsInitialized returns whether the filter has processed at least one update.
+func (cf *ComplementaryFilter) IsInitialized() bool {
+	return cf.initialized
+}
+
+// MahonyFilter is a single-axis Mahony-style attitude filter.
+//
+// Like the ComplementaryFilter it fuses a gyro rate with an accelerometer angle, but the
+// accelerometer correction is applied through a proportional-integral feedback on the gyro
+// rate. The integral term converges to the gyro bias, so a constant gyro drift produces no
+// steady-state angle error:
+//
+//	e = accelAngle - angle
+//	bias -= ki * e * dt
+//	angle += (gyroRate - bias + kp*e) * dt
+type MahonyFilter struct {
+	kp          float64 // Proportional correction gain
+	ki          float64 // Integral (bias) correction gain
+	angle       float64 // Fused angle estimate
+	bias        float64 // Gyro bias estimate
+	initialized bool    // Whether the filter has been initialized
+}
+
+// NewMahonyFilter creates a new Mahony-style attitude filter with explicit gains.
+//
+// Parameters:
+//   - kp: Proportional correction gain in 1/s
+//   - ki: Integral correction gain in 1/s^2. Zero disables bias estimation.
+//
+// Returns an error if kp is not positive or ki is negative.
+func NewMahonyFilter(kp, ki float64) (*MahonyFilter, error) {
+	if kp <= 0 {
+		return nil, errors.New("kp must be positive")
+	}
+	if ki < 0 {
+		return nil, errors.New("ki must be non-negative")
+	}
+
+	return &MahonyFilter{
+		kp: kp,
+		ki: ki,
+	}, nil
+}
+
+// NewMahonyFilterFromTimeConstant creates a critically damped Mahony-style filter whose
+// crossover time constant is tau seconds (kp = 2/tau, ki = 1/tau^2).
+//
+// Returns an error if the time constant is not positive.
+func NewMahonyFilterFromTimeConstant(timeConstant float64) (*MahonyFilter, error) {
+	if timeConstant <= 0 {
+		return nil, errors.New("time constant must be positive")
+	}
+	return NewMahonyFilter(2/timeConstant, 1/(timeConstant*timeConstant))
+}
+
+// Update fuses a gyro rate and an accelerometer angle measured dt seconds after the previous
+// update and returns the fused angle. The angle and rate must use consistent units
+// (e.g. radians and radians per second).
+//
+// On the first call, the filter is initialized with the accelerometer angle.
+func (mf *MahonyFilter) Update(gyroRate, accelAngle, dt float64) float64 {
+	if !mf.initialized {
+		mf.angle = accelAngle
+		mf.initialized = true
+		return mf.angle
+	}
+	if dt <= 0 {
+		return mf.angle
+	}
+
+	e := accelAngle - mf.angle
+	mf.bias -= mf.ki * e * dt
+	mf.angle += (gyroRate - mf.bias + mf.kp*e) * dt
+	return mf.angle
+}
+
+// GetAngle returns the current fused angle.
+func (mf *MahonyFilter) GetAngle() float64 {
+	return mf.angle
+}
+
+// GetBias returns the current gyro bias estimate.
+func (mf *MahonyFilter) GetBias() float64 {
+	return mf.bias
+}
+
+// SetAngle sets the fused angle and marks the filter as initialized.
+func (mf *MahonyFilter) SetAngle(angle float64) {
+	mf.angle = angle
+	mf.initialized = true
+}
+
+// GetGains returns the proportional and integral correction gains.
+func (mf *MahonyFilter) GetGains() (kp, ki float64) {
+	return mf.kp, mf.ki
+}
+
+// Reset resets the filter to its uninitialized state and clears the bias estimate.
+// The next call to Update will initialize the filter with the accelerometer angle.
+func (mf *MahonyFilter) Reset() {
+	mf.angle = 0.0
+	mf.bias = 0.0
+	mf.initialized = false
+}
+
+// IsInitialized returns whether the filter has processed at least one update.
+func (mf *MahonyFilter) IsInitialized() bool {
+	return mf.initialized
+}
@@ -0,0 +1,155 @@
+package filter
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// imuSample is a simulated gyro rate and accelerometer angle with the true angle.
+type imuSample struct {
+	gyroRate, accelAngle, truth float64
+}
+
+// simulateIMU produces a slowly oscillating attitude measured by a biased gyro and a noisy accelerometer.
+func simulateIMU(steps int, dt, gyroBias, accelNoise float64, seed int64) []imuSample {
+	rng := rand.New(rand.NewSource(seed))
+	samples := make([]imuSample, steps)
+	for i := range samples {
+		t := float64(i) * dt
+		truth := 0.5 * math.Sin(0.5*t)
+		rate := 0.25 * math.Cos(0.5*t)
+		samples[i] = imuSample{
+			gyroRate:   rate + gyroBias + rng.NormFloat64()*0.01,
+			accelAngle: truth + rng.NormFloat64()*accelNoise,
+			truth:      truth,
+		}
+	}
+	return samples
+}
+
+// TestComplementaryFilter tests the ComplementaryFilter functionality
+func TestComplementaryFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewComplementaryFilter(0.0); err == nil {
+			t.Error("Expected error for zero time constant")
+		}
+		cf, err := NewComplementaryFilter(0.5)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cf.GetTimeConstant() != 0.5 {
+			t.Errorf("Expected time constant 0.5, got %f", cf.GetTimeConstant())
+		}
+	})
+
+	t.Run("Bounded drift", func(t *testing.T) {
+		dt := 0.01
+		samples := simulateIMU(6000, dt, 0.05, 0.1, 1)
+		cf, _ := NewComplementaryFilter(0.5)
+
+		integrated := samples[0].accelAngle
+		var maxFusedError, rawError, fusedError float64
+		for i, s := range samples {
+			angle := cf.Update(s.gyroRate, s.accelAngle, dt)
+			integrated += s.gyroRate * dt
+
+			if i > 500 {
+				maxFusedError = math.Max(maxFusedError, math.Abs(angle-s.truth))
+				rawError += (s.accelAngle - s.truth) * (s.accelAngle - s.truth)
+				fusedError += (angle - s.truth) * (angle - s.truth)
+			}
+		}
+
+		// Integrating the gyro alone drifts by bias * time
+		if drift := math.Abs(integrated - samples[len(samples)-1].truth); drift < 2.0 {
+			t.Errorf("Expected pure gyro integration to drift, got %f", drift)
+		}
+		// The fused angle stays within tau * bias plus noise
+		if maxFusedError > 0.1 {
+			t.Errorf("Expected bounded fused error, got %f", maxFusedError)
+		}
+		if fusedError >= rawError {
+			t.Errorf("Expected fused error %f to be less than accelerometer error %f", fusedError, rawError)
+		}
+	})
+
+	t.Run("Reset functionality", func(t *testing.T) {
+		cf, _ := NewComplementaryFilter(0.5)
+		cf.Update(0.0, 1.0, 0.01)
+		cf.Update(1.0, 1.0, 0.01)
+
+		cf.Reset()
+		if cf.IsInitialized() {
+			t.Error("Expected filter to be uninitialized after reset")
+		}
+		if angle := cf.Update(5.0, 0.3, 0.01); angle != 0.3 {
+			t.Errorf("Expected first update after reset to return accelerometer angle, got %f", angle)
+		}
+	})
+}
+
+// TestMahonyFilter tests the MahonyFilter functionality
+func TestMahonyFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewMahonyFilter(0.0, 0.1); err == nil {
+			t.Error("Expected error for zero kp")
+		}
+		if _, err := NewMahonyFilter(1.0, -0.1); err == nil {
+			t.Error("Expected error for negative ki")
+		}
+		if _, err := NewMahonyFilterFromTimeConstant(-1.0); err == nil {
+			t.Error("Expected error for negative time constant")
+		}
+
+		mf, err := NewMahonyFilterFromTimeConstant(0.5)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if kp, ki := mf.GetGains(); kp != 4.0 || ki != 4.0 {
+			t.Errorf("Expected gains (4, 4), got (%f, %f)", kp, ki)
+		}
+	})
+
+	t.Run("Bias estimation removes drift", func(t *testing.T) {
+		dt := 0.01
+		bias := 0.05
+		samples := simulateIMU(6000, dt, bias, 0.1, 2)
+
+		mf, _ := NewMahonyFilterFromTimeConstant(0.5)
+		cf, _ := NewComplementaryFilter(0.5)
+
+		var mahonyError, complementaryError float64
+		for i, s := range samples {
+			angle := mf.Update(s.gyroRate, s.accelAngle, dt)
+			cfAngle := cf.Update(s.gyroRate, s.accelAngle, dt)
+			if i > 3000 {
+				mahonyError += angle - s.truth
+				complementaryError += cfAngle - s.truth
+			}
+		}
+		mahonyError /= 2999
+		complementaryError /= 2999
+
+		if math.Abs(mf.GetBias()-bias) > 0.01 {
+			t.Errorf("Expected bias estimate near %f, got %f", bias, mf.GetBias())
+		}
+		if math.Abs(mahonyError) >= math.Abs(complementaryError) {
+			t.Errorf("Expected Mahony mean error %f to be smaller than complementary mean error %f",
+				mahonyError, complementaryError)
+		}
+	})
+
+	t.Run("Reset functionality", func(t *testing.T) {
+		mf, _ := NewMahonyFilter(2.0, 1.0)
+		mf.Update(0.0, 0.0, 0.01)
+		for range 100 {
+			mf.Update(0.0, 1.0, 0.01)
+		}
+
+		mf.Reset()
+		if mf.IsInitialized() || mf.GetBias() != 0.0 {
+			t.Error("Expected filter to be uninitialized with zero bias after reset")
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package filter
+
+import (
+	"errors"
+	"time"
+)
+
+// Clock returns the current time. It can be replaced in tests or simulations to control the
+// time seen by the signal conditioners.
+type Clock func() time.Time
+
+// elapsedTimer measures the time between calls using a Clock.
+type elapsedTimer struct {
+	clock   Clock     // Source of the current time
+	last    time.Time // Time of the previous call
+	started bool      // Whether a call has been made
+}
+
+// elapsed returns the seconds since the previous call, or 0 on the first call.
+func (et *elapsedTimer) elapsed() float64 {
+	now := et.clock()
+	if !et.started {
+		et.last = now
+		et.started = true
+		return 0.0
+	}
+	dt := now.Sub(et.last).Seconds()
+	et.last = now
+	return dt
+}
+
+// reset forgets the time of the previous call.
+func (et *elapsedTimer) reset() {
+	et.started = false
+}
+
+// SlewRateLimiter limits the rate at which a signal may rise and fall.
+//
+// It is typically applied to setpoints from joysticks or operators so that a step change is
+// turned into a ramp before it reaches a controller. The first input passes through unchanged.
+type SlewRateLimiter struct {
+	rise        float64      // Maximum increase per second
+	fall        float64      // Maximum decrease per second
+	value       float64      // Current output
+	initialized bool         // Whether an input has been received
+	timer       elapsedTimer // Measures dt for Calculate
+}
+
+// NewSlewRateLimiter creates a new slew rate limiter.
+//
+// Parameters:
+//   - rise: Maximum rate of increase in units per second
+//   - fall: Maximum rate of decrease in units per second, given as a positive number
+//
+// Returns an error if either rate is not positive.
+func NewSlewRateLimiter(rise, fall float64) (*SlewRateLimiter, error) {
+	if rise <= 0 || fall <= 0 {
+		return nil, errors.New("rates must be positive")
+	}
+
+	return &SlewRateLimiter{
+		rise:  rise,
+		fall:  fall,
+		timer: elapsedTimer{clock: time.Now},
+	}, nil
+}
+
+// Calculate limits the input using the time elapsed since the previous call, measured by the
+// limiter's clock. The first call returns the input unchanged.
+func (srl *SlewRateLimiter) Calculate(input float64) float64 {
+	return srl.CalculateWithDt(input, srl.timer.elapsed())
+}
+
+// CalculateWithDt limits the input, allowing the output to move by at most the rise or fall
+// rate multiplied by dt. The first call returns the input unchanged.
+func (srl *SlewRateLimiter) CalculateWithDt(input, dt float64) float64 {
+	if !srl.initialized {
+		srl.value = input
+		srl.initialized = true
+		return srl.value
+	}
+	if dt <= 0 {
+		return srl.value
+	}
+
+	change := input - srl.value
+	change = min(change, srl.rise*dt)
+	change = max(change, -srl.fall*dt)
+	srl.value += change
+	return srl.value
+}
+
+// GetValue returns the current output.
+func (srl *SlewRateLimiter) GetValue() float64 {
+	return srl.value
+}
+
+// SetValue sets the current output, from which the next input is limited.
+func (srl *SlewRateLimiter) SetValue(value float64) {
+	srl.value = value
+	srl.initialized = true
+}
+
+// GetRates returns the maximum rise and fall rates.
+func (srl *SlewRateLimiter) GetRates() (rise, fall float64) {
+	return srl.rise, srl.fall
+}
+
+// SetClock sets the clock used by Calculate. A nil clock restores time.Now.
+func (srl *SlewRateLimiter) SetClock(clock Clock) {
+	if clock == nil {
+		clock = time.Now
+	}
+	srl.timer = elapsedTimer{clock: clock}
+}
+
+// Reset clears the output. The next input passes through unchanged.
+func (srl *SlewRateLimiter) Reset() {
+	srl.value = 0.0
+	srl.initialized = false
+	srl.timer.reset()
+}
+
+// DebounceType selects which transitions of a Debouncer are delayed.
+type DebounceType int
+
+const (
+	// DebounceRising delays false-to-true transitions. The output only becomes true once the
+	// input has been true for the debounce time, and becomes false immediately.
+	DebounceRising DebounceType = iota
+
+	// DebounceFalling delays true-to-false transitions. The output only becomes false once the
+	// input has been false for the debounce time, and becomes true immediately.
+	DebounceFalling
+
+	// DebounceBoth delays both transitions.
+	DebounceBoth
+)
+
+// Debouncer removes chatter from a boolean input such as a limit switch by requiring a change
+// to persist for a debounce time before it is passed through.
+type Debouncer struct {
+	debounceTime float64      // Time a change must persist, in seconds
+	debounceType DebounceType // Transitions that are delayed
+	baseline     bool         // Current debounced state
+	elapsed      float64      // Time the input has differed from the baseline
+	timer        elapsedTimer // Measures dt for Calculate
+}
+
+// NewDebouncer creates a new debouncer.
+//
+// Parameters:
+//   - debounceTime: Time in seconds a change must persist before it is passed through
+//   - debounceType: Which transitions are delayed
+//
+// The initial output is false, except for DebounceFalling where it is true.
+// Returns an error if the debounce time is negative or the type is unknown.
+func NewDebouncer(debounceTime float64, debounceType DebounceType) (*Debouncer, error) {
+	if debounceTime < 0 {
+		return nil, errors.New("debounce time must be non-negative")
+	}
+	if debounceType != DebounceRising && debounceType != DebounceFalling && debounceType != DebounceBoth {
+		return nil, errors.New("unknown debounce type")
+	}
+
+	d := &Debouncer{
+		debounceTime: debounceTime,
+		debounceType: debounceType,
+		timer:        elapsedTimer{clock: time.Now},
+	}
+	d.Reset()
+	return d, nil
+}
+
+// Calculate debounces the input using the time elapsed since the previous call, measured by
+// the debouncer's clock.
+func (d *Debouncer) Calculate(input bool) bool {
+	return d.CalculateWithDt(input, d.timer.elapsed())
+}
+
+// CalculateWithDt debounces an input that has been present for dt seconds and returns the
+// debounced output.
+func (d *Debouncer) CalculateWithDt(input bool, dt float64) bool {
+	if input == d.baseline {
+		d.elapsed = 0
+		return d.baseline
+	}
+
+	d.elapsed += max(dt, 0)
+	if d.elapsed < d.debounceTime {
+		return d.baseline
+	}
+
+	// The change has persisted long enough
+	if d.debounceType == DebounceBoth {
+		d.baseline = input
+		d.elapsed = 0
+	}
+	return input
+}
+
+// GetDebounceTime returns the time a change must persist, in seconds.
+func (d *Debouncer) GetDebounceTime() float64 {
+	return d.debounceTime
+}
+
+// GetDebounceType returns which transitions are delayed.
+func (d *Debouncer) GetDebounceType() DebounceType {
+	return d.debounceType
+}
+
+// SetClock sets the clock used by Calculate. A nil clock restores time.Now.
+func (d *Debouncer) SetClock(clock Clock) {
+	if clock == nil {
+		clock = time.Now
+	}
+	d.timer = elapsedTimer{clock: clock}
+}
+
+// Reset restores the initial output and clears the elapsed time.
+func (d *Debouncer) Reset() {
+	d.baseline = d.debounceType == DebounceFalling
+	d.elapsed = 0
+	d.timer.reset()
+}
@@ -0,0 +1,203 @@
+package filter
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced Clock for tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.now = fc.now.Add(d)
+}
+
+// TestSlewRateLimiter tests the SlewRateLimiter functionality
+func TestSlewRateLimiter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewSlewRateLimiter(0, 1); err == nil {
+			t.Error("Expected error for zero rise rate")
+		}
+		if _, err := NewSlewRateLimiter(1, -1); err == nil {
+			t.Error("Expected error for negative fall rate")
+		}
+		srl, err := NewSlewRateLimiter(2, 4)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if rise, fall := srl.GetRates(); rise != 2 || fall != 4 {
+			t.Errorf("Expected rates 2 and 4, got %f and %f", rise, fall)
+		}
+	})
+
+	t.Run("Asymmetric ramp", func(t *testing.T) {
+		srl, _ := NewSlewRateLimiter(2, 4)
+		if got := srl.CalculateWithDt(1.0, 0.1); got != 1.0 {
+			t.Errorf("Expected first input to pass through, got %f", got)
+		}
+
+		// Rises at 2 per second
+		var got float64
+		for range 10 {
+			got = srl.CalculateWithDt(10.0, 0.1)
+		}
+		if math.Abs(got-3.0) > 1e-9 {
+			t.Errorf("Expected 3 after rising for 1 s, got %f", got)
+		}
+
+		// Falls at 4 per second
+		got = srl.CalculateWithDt(-10.0, 0.5)
+		if math.Abs(got-1.0) > 1e-9 {
+			t.Errorf("Expected 1 after falling for 0.5 s, got %f", got)
+		}
+
+		// Small changes are not limited
+		got = srl.CalculateWithDt(1.05, 0.1)
+		if math.Abs(got-1.05) > 1e-9 {
+			t.Errorf("Expected 1.05, got %f", got)
+		}
+
+		if got := srl.CalculateWithDt(5.0, 0); got != srl.GetValue() || math.Abs(got-1.05) > 1e-9 {
+			t.Errorf("Expected zero dt to hold the output, got %f", got)
+		}
+	})
+
+	t.Run("Injectable clock", func(t *testing.T) {
+		clock := &fakeClock{now: time.Unix(100, 0)}
+		srl, _ := NewSlewRateLimiter(1, 1)
+		srl.SetClock(clock.Now)
+
+		srl.Calculate(0.0)
+		clock.Advance(250 * time.Millisecond)
+		if got := srl.Calculate(1.0); math.Abs(got-0.25) > 1e-9 {
+			t.Errorf("Expected 0.25 after 250 ms, got %f", got)
+		}
+	})
+
+	t.Run("SetValue and Reset", func(t *testing.T) {
+		srl, _ := NewSlewRateLimiter(1, 1)
+		srl.SetValue(5.0)
+		if got := srl.CalculateWithDt(0.0, 1.0); math.Abs(got-4.0) > 1e-9 {
+			t.Errorf("Expected 4 after SetValue, got %f", got)
+		}
+		srl.Reset()
+		if got := srl.CalculateWithDt(-3.0, 1.0); got != -3.0 {
+			t.Errorf("Expected input to pass through after reset, got %f", got)
+		}
+	})
+}
+
+// TestDebouncer tests the Debouncer functionality
+func TestDebouncer(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewDebouncer(-0.1, DebounceRising); err == nil {
+			t.Error("Expected error for negative debounce time")
+		}
+		if _, err := NewDebouncer(0.1, DebounceType(7)); err == nil {
+			t.Error("Expected error for unknown debounce type")
+		}
+		d, err := NewDebouncer(0.1, DebounceBoth)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if d.GetDebounceTime() != 0.1 || d.GetDebounceType() != DebounceBoth {
+			t.Error("Expected getters to return the configuration")
+		}
+	})
+
+	// Steps the debouncer with 10 ms samples and returns the outputs
+	run := func(d *Debouncer, inputs []bool) []bool {
+		outputs := make([]bool, len(inputs))
+		for i, input := range inputs {
+			outputs[i] = d.CalculateWithDt(input, 0.01)
+		}
+		return outputs
+	}
+	repeat := func(value bool, n int) []bool {
+		values := make([]bool, n)
+		for i := range values {
+			values[i] = value
+		}
+		return values
+	}
+
+	t.Run("Rising", func(t *testing.T) {
+		d, _ := NewDebouncer(0.05, DebounceRising)
+
+		// Chatter shorter than the debounce time is rejected
+		chatter := []bool{true, false, true, true, false, true, false}
+		for i, output := range run(d, chatter) {
+			if output {
+				t.Errorf("Sample %d: expected chatter to be rejected", i)
+			}
+		}
+
+		outputs := run(d, repeat(true, 8))
+		if outputs[3] || !outputs[4] || !outputs[7] {
+			t.Errorf("Expected output to rise on the fifth sample, got %v", outputs)
+		}
+
+		// Falling edges pass immediately
+		if run(d, []bool{false})[0] {
+			t.Error("Expected falling edge to pass immediately")
+		}
+	})
+
+	t.Run("Falling", func(t *testing.T) {
+		d, _ := NewDebouncer(0.05, DebounceFalling)
+		if !run(d, []bool{true})[0] {
+			t.Error("Expected initial output to be true")
+		}
+
+		outputs := run(d, repeat(false, 6))
+		if outputs[4] || outputs[5] {
+			t.Errorf("Expected output to fall after the debounce time, got %v", outputs)
+		}
+		if !outputs[3] {
+			t.Errorf("Expected output to stay true within the debounce time, got %v", outputs)
+		}
+	})
+
+	t.Run("Both", func(t *testing.T) {
+		d, _ := NewDebouncer(0.03, DebounceBoth)
+
+		outputs := run(d, repeat(true, 4))
+		if outputs[1] || !outputs[2] {
+			t.Errorf("Expected rising edge after the debounce time, got %v", outputs)
+		}
+
+		outputs = run(d, []bool{false, true, false, false, false})
+		if !outputs[0] || !outputs[1] || !outputs[3] || outputs[4] {
+			t.Errorf("Expected falling edge to be debounced, got %v", outputs)
+		}
+	})
+
+	t.Run("Injectable clock and reset", func(t *testing.T) {
+		clock := &fakeClock{now: time.Unix(0, 0)}
+		d, _ := NewDebouncer(0.1, DebounceRising)
+		d.SetClock(clock.Now)
+
+		d.Calculate(false)
+		clock.Advance(60 * time.Millisecond)
+		if d.Calculate(true) {
+			t.Error("Expected output to stay false before the debounce time")
+		}
+		clock.Advance(60 * time.Millisecond)
+		if !d.Calculate(true) {
+			t.Error("Expected output to rise after the debounce time")
+		}
+
+		d.Reset()
+		clock.Advance(time.Second)
+		if d.Calculate(true) {
+			t.Error("Expected reset to restart the debounce time")
+		}
+	})
+}
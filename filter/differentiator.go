@@ -0,0 +1,249 @@
+package filter
+
+import (
+	"errors"
+	"math"
+)
+
+// FirstDifference estimates the derivative as the change between consecutive samples divided by dt.
+// It has no lag but amplifies noise, particularly on quantized signals.
+type FirstDifference struct {
+	last        float64 // Previous sample
+	initialized bool    // Whether a sample has been recorded
+}
+
+// NewFirstDifference creates a new first-difference differentiator.
+func NewFirstDifference() *FirstDifference {
+	return &FirstDifference{}
+}
+
+// Differentiate records the value and returns (value - previous) / dt.
+// The first sample, or a non-positive dt, records the value and returns 0.
+// This implements the Differentiator interface.
+func (fd *FirstDifference) Differentiate(value, dt float64) float64 {
+	last := fd.last
+	initialized := fd.initialized
+	fd.last = value
+	fd.initialized = true
+
+	if !initialized || dt <= 0 {
+		return 0
+	}
+	return (value - last) / dt
+}
+
+// Reset forgets the previous sample.
+func (fd *FirstDifference) Reset() {
+	fd.last = 0
+	fd.initialized = false
+}
+
+// FilteredDerivative passes the first-difference derivative through a Filter, such as a
+// LowPassFilter or KalmanFilter, to reduce noise.
+type FilteredDerivative struct {
+	difference *FirstDifference
+	filter     Filter
+}
+
+// NewFilteredDerivative creates a differentiator that filters the first-difference derivative
+// with the given filter.
+//
+// Returns an error if the filter is nil.
+func NewFilteredDerivative(f Filter) (*FilteredDerivative, error) {
+	if f == nil {
+		return nil, errors.New("filter must not be nil")
+	}
+
+	return &FilteredDerivative{
+		difference: NewFirstDifference(),
+		filter:     f,
+	}, nil
+}
+
+// Differentiate records the value and returns the filtered first-difference derivative.
+// The first sample, or a non-positive dt, records the value and returns 0 without updating the filter.
+// This implements the Differentiator interface.
+func (fd *FilteredDerivative) Differentiate(value, dt float64) float64 {
+	initialized := fd.difference.initialized
+	derivative := fd.difference.Differentiate(value, dt)
+	if !initialized || dt <= 0 {
+		return 0
+	}
+	return fd.filter.Estimate(derivative)
+}
+
+// GetFilter returns the filter applied to the derivative.
+func (fd *FilteredDerivative) GetFilter() Filter {
+	return fd.filter
+}
+
+// Reset forgets the previous sample and resets the filter.
+func (fd *FilteredDerivative) Reset() {
+	fd.difference.Reset()
+	fd.filter.Reset()
+}
+
+// RegressionDerivative estimates the derivative as the slope of a least squares line fitted to
+// the most recent N samples. The sample times are accumulated from dt, so irregular sampling
+// is handled correctly.
+type RegressionDerivative struct {
+	regression *LinearRegression
+	time       float64 // Time of the newest sample
+}
+
+// NewRegressionDerivative creates a differentiator that fits a line to the most recent size samples.
+//
+// Returns an error if size is less than 2.
+func NewRegressionDerivative(size int) (*RegressionDerivative, error) {
+	if size < 2 {
+		return nil, errors.New("window size must be at least 2")
+	}
+
+	regression, err := NewPolynomialRegression(1, size, 1.0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegressionDerivative{
+		regression: regression,
+	}, nil
+}
+
+// Differentiate records the value dt seconds after the previous sample and returns the slope
+// of the fitted line. A non-positive dt records the value at the same time as the previous
+// sample and returns 0.
+// This implements the Differentiator interface.
+func (rd *RegressionDerivative) Differentiate(value, dt float64) float64 {
+	if rd.regression.Len() > 0 && dt > 0 {
+		rd.time += dt
+	}
+	rd.regression.PushAt(rd.time, value)
+
+	if dt <= 0 {
+		return 0
+	}
+	return rd.regression.Derivative(rd.time)
+}
+
+// GetSize returns the number of samples the line is fitted to.
+func (rd *RegressionDerivative) GetSize() int {
+	return rd.regression.window
+}
+
+// Reset clears the window.
+func (rd *RegressionDerivative) Reset() {
+	rd.regression.Reset()
+	rd.time = 0
+}
+
+// TrackingDifferentiator implements Han's nonlinear tracking differentiator from active
+// disturbance rejection control.
+//
+// The differentiator drives an internal double integrator to track the input as fast as
+// possible subject to an acceleration limit r, using the time-optimal synthesis function
+// fhan. The integrator's velocity is the derivative estimate. Larger r tracks faster but
+// passes more noise; a filter factor h0 larger than the sample time adds smoothing.
+type TrackingDifferentiator struct {
+	r           float64 // Speed factor (acceleration limit)
+	h0          float64 // Filter factor in seconds, or 0 to use the sample time
+	x1          float64 // Tracked value
+	x2          float64 // Derivative estimate
+	initialized bool    // Whether a sample has been recorded
+}
+
+// NewTrackingDifferentiator creates a new tracking differentiator.
+//
+// Parameters:
+//   - r: Speed factor, the largest second derivative the tracker can follow
+//   - h0: Filter factor in seconds. Zero uses the sample time; larger values smooth more.
+//
+// Returns an error if r is not positive or h0 is negative.
+func NewTrackingDifferentiator(r, h0 float64) (*TrackingDifferentiator, error) {
+	if r <= 0 {
+		return nil, errors.New("speed factor must be positive")
+	}
+	if h0 < 0 {
+		return nil, errors.New("filter factor must be non-negative")
+	}
+
+	return &TrackingDifferentiator{
+		r:  r,
+		h0: h0,
+	}, nil
+}
+
+// Differentiate advances the tracker by dt towards the value and returns the derivative estimate.
+// The first sample initializes the tracker at the value with zero derivative. A non-positive
+// dt returns 0 without advancing the tracker.
+// This implements the Differentiator interface.
+func (td *TrackingDifferentiator) Differentiate(value, dt float64) float64 {
+	if !td.initialized {
+		td.x1 = value
+		td.x2 = 0
+		td.initialized = true
+		return 0
+	}
+	if dt <= 0 {
+		return 0
+	}
+
+	h0 := td.h0
+	if h0 == 0 {
+		h0 = dt
+	}
+
+	u := fhan(td.x1-value, td.x2, td.r, h0)
+	td.x1 += dt * td.x2
+	td.x2 += dt * u
+	return td.x2
+}
+
+// GetValue returns the tracked value.
+func (td *TrackingDifferentiator) GetValue() float64 {
+	return td.x1
+}
+
+// GetDerivative returns the current derivative estimate.
+func (td *TrackingDifferentiator) GetDerivative() float64 {
+	return td.x2
+}
+
+// Reset resets the tracker. The next sample reinitializes it.
+func (td *TrackingDifferentiator) Reset() {
+	td.x1 = 0
+	td.x2 = 0
+	td.initialized = false
+}
+
+// fhan is Han's discrete time-optimal control synthesis function for a double integrator
+// with position error x1, velocity x2, acceleration limit r and step h.
+func fhan(x1, x2, r, h float64) float64 {
+	d := r * h
+	d0 := h * d
+	y := x1 + h*x2
+	a0 := math.Sqrt(d*d + 8*r*math.Abs(y))
+
+	var a float64
+	if math.Abs(y) > d0 {
+		a = x2 + (a0-d)/2*sign(y)
+	} else {
+		a = x2 + y/h
+	}
+
+	if math.Abs(a) > d {
+		return -r * sign(a)
+	}
+	return -r * a / d
+}
+
+// sign returns -1, 0 or 1 according to the sign of x.
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,149 @@
+package filter
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestDifferentiators tests the Differentiator implementations
+func TestDifferentiators(t *testing.T) {
+	newDifferentiators := func(t *testing.T) map[string]Differentiator {
+		lpf, _ := NewLowPassFilter(0.5)
+		filtered, err := NewFilteredDerivative(lpf)
+		if err != nil {
+			t.Fatalf("NewFilteredDerivative: %v", err)
+		}
+		regression, err := NewRegressionDerivative(10)
+		if err != nil {
+			t.Fatalf("NewRegressionDerivative: %v", err)
+		}
+		sg, _ := NewSavitzkyGolayFilter(11, 2, 0.01)
+		tracking, err := NewTrackingDifferentiator(1000, 0.05)
+		if err != nil {
+			t.Fatalf("NewTrackingDifferentiator: %v", err)
+		}
+		return map[string]Differentiator{
+			"FirstDifference":        NewFirstDifference(),
+			"FilteredDerivative":     filtered,
+			"RegressionDerivative":   regression,
+			"SavitzkyGolayFilter":    sg,
+			"TrackingDifferentiator": tracking,
+		}
+	}
+
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewFilteredDerivative(nil); err == nil {
+			t.Error("Expected error for nil filter")
+		}
+		if _, err := NewRegressionDerivative(1); err == nil {
+			t.Error("Expected error for window smaller than 2")
+		}
+		if _, err := NewTrackingDifferentiator(0, 0); err == nil {
+			t.Error("Expected error for zero speed factor")
+		}
+		if _, err := NewTrackingDifferentiator(10, -0.1); err == nil {
+			t.Error("Expected error for negative filter factor")
+		}
+	})
+
+	t.Run("First sample and non-positive dt return zero", func(t *testing.T) {
+		for name, d := range newDifferentiators(t) {
+			if got := d.Differentiate(5.0, 0.01); got != 0 {
+				t.Errorf("%s: expected 0 for the first sample, got %f", name, got)
+			}
+			if got := d.Differentiate(6.0, 0); got != 0 {
+				t.Errorf("%s: expected 0 for zero dt, got %f", name, got)
+			}
+		}
+	})
+
+	t.Run("Ramp", func(t *testing.T) {
+		const dt, slope = 0.01, 3.0
+		for name, d := range newDifferentiators(t) {
+			var got float64
+			for i := range 500 {
+				got = d.Differentiate(slope*float64(i)*dt, dt)
+			}
+			if math.Abs(got-slope) > 1e-6 {
+				t.Errorf("%s: expected derivative %f, got %f", name, slope, got)
+			}
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		for name, d := range newDifferentiators(t) {
+			for i := range 50 {
+				d.Differentiate(float64(i), 0.01)
+			}
+			d.Reset()
+			if got := d.Differentiate(100.0, 0.01); got != 0 {
+				t.Errorf("%s: expected 0 after reset, got %f", name, got)
+			}
+		}
+	})
+
+	t.Run("Noise rejection", func(t *testing.T) {
+		const dt, slope = 0.01, 2.0
+		rng := rand.New(rand.NewSource(1))
+
+		rms := make(map[string]float64)
+		differentiators := newDifferentiators(t)
+		for i := range 2000 {
+			value := slope*float64(i)*dt + 0.01*rng.NormFloat64()
+			for name, d := range differentiators {
+				got := d.Differentiate(value, dt)
+				if i >= 100 {
+					rms[name] += (got - slope) * (got - slope)
+				}
+			}
+		}
+
+		for name := range differentiators {
+			if name == "FirstDifference" {
+				continue
+			}
+			if rms[name] >= rms["FirstDifference"] {
+				t.Errorf("%s: expected less noise than the first difference, got %f >= %f",
+					name, rms[name], rms["FirstDifference"])
+			}
+		}
+	})
+
+	t.Run("Regression with irregular sampling", func(t *testing.T) {
+		rd, _ := NewRegressionDerivative(5)
+		if rd.GetSize() != 5 {
+			t.Errorf("Expected size 5, got %d", rd.GetSize())
+		}
+
+		var tm, got float64
+		rd.Differentiate(0, 0.01)
+		for i := range 20 {
+			dt := 0.01 + 0.005*float64(i%3)
+			tm += dt
+			got = rd.Differentiate(-4*tm, dt)
+		}
+		if math.Abs(got+4) > 1e-9 {
+			t.Errorf("Expected derivative -4, got %f", got)
+		}
+	})
+
+	t.Run("Tracking differentiator follows a sine", func(t *testing.T) {
+		td, _ := NewTrackingDifferentiator(500, 0)
+		const dt = 0.001
+		var maxErr float64
+		for i := range 5000 {
+			tm := float64(i) * dt
+			got := td.Differentiate(math.Sin(2*math.Pi*tm), dt)
+			if tm > 1 {
+				maxErr = max(maxErr, math.Abs(got-2*math.Pi*math.Cos(2*math.Pi*tm)))
+			}
+		}
+		if maxErr > 0.5 {
+			t.Errorf("Expected tracking error below 0.5, got %f", maxErr)
+		}
+		if math.Abs(td.GetValue()-math.Sin(2*math.Pi*4.999)) > 0.05 {
+			t.Errorf("Expected tracked value near the input, got %f", td.GetValue())
+		}
+	})
+}
@@ -8,6 +8,46 @@ type Filter interface {
 	// Reset resets the filter to its initial state.
 	Reset()
 
-	// GetGain retrieves the gain of the filter, if applicable.
+	// GetGain retrieves the gain of the filter, if applicable. Its meaning depends on the
+	// filter, for example the weight on the previous estimate for LowPassFilter and the weight
+	// on the newest sample for MovingAverageFilter, so gains of different filters cannot be
+	// compared or combined.
 	GetGain() float64
 }
+
+// BatchFilter is implemented by filters that process a slice of measurements faster than
+// calling Estimate for each, such as LowPassFilter and ChainFilter. Use the EstimateAll
+// function to process a slice with any filter.
+type BatchFilter interface {
+	Filter
+
+	// EstimateAll processes each measurement in order as if passed to Estimate, writing the
+	// estimates to out, which must be at least as long as in. in and out may be the same slice.
+	EstimateAll(in, out []float64)
+}
+
+// EstimateAll processes each measurement in order, writing the estimates to out, which must be
+// at least as long as in. It uses the filter's own EstimateAll if it implements BatchFilter and
+// otherwise calls Estimate for each measurement.
+func EstimateAll(f Filter, in, out []float64) {
+	if bf, ok := f.(BatchFilter); ok {
+		bf.EstimateAll(in, out)
+		return
+	}
+
+	out = out[:len(in)]
+	for i, measurement := range in {
+		out[i] = f.Estimate(measurement)
+	}
+}
+
+// Differentiator defines the interface for estimating the derivative of a sampled signal.
+type Differentiator interface {
+	// Differentiate processes a sample taken dt seconds after the previous one and returns
+	// the estimated derivative. The first sample after construction or Reset, and any
+	// sample with a non-positive dt, is recorded and returns 0.
+	Differentiate(value, dt float64) float64
+
+	// Reset resets the differentiator to its initial state.
+	Reset()
+}
@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// madScale converts the median absolute deviation into an estimate of the standard
+// deviation for normally distributed data.
+const madScale = 1.4826
+
+// HampelFilter implements a causal Hampel outlier-rejection filter.
+//
+// Each measurement is compared with the median of the most recent N samples. If it lies
+// more than threshold scaled median absolute deviations (MAD) from the median it is treated
+// as an outlier and replaced by the median; otherwise it is passed through unchanged. This
+// removes spikes while leaving the shape of a clean signal untouched.
+//
+// On a plateau or a quantized signal such as an encoder count the MAD is zero, so without a
+// floor any change, even a single count, would be rejected until it fills half the window.
+// SetMinDeviation sets a floor on the scaled MAD for such signals, for example one count.
+type HampelFilter struct {
+	threshold    float64       // Number of scaled MADs beyond which a sample is an outlier
+	minDeviation float64       // Floor on the scaled MAD
+	median       *MedianFilter // Running median of the window
+	window       *Float64Stack // Raw samples in arrival order, used for the MAD
+	scratch      []float64     // Reusable buffer for the absolute deviations
+	outlier      bool          // Whether the last measurement was rejected
+}
+
+// NewHampelFilter creates a new Hampel filter.
+//
+// Parameters:
+//   - size: Number of samples in the sliding window
+//   - threshold: Number of scaled median absolute deviations beyond which a sample is
+//     rejected. A value of 3 is a common choice.
+//
+// Returns an error if size is not positive or threshold is negative.
+func NewHampelFilter(size int, threshold float64) (*HampelFilter, error) {
+	if threshold < 0 {
+		return nil, errors.New("threshold must be non-negative")
+	}
+	median, err := NewMedianFilter(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HampelFilter{
+		threshold: threshold,
+		median:    median,
+		window:    NewFloat64Stack(size),
+		scratch:   make([]float64, 0, size),
+	}, nil
+}
+
+// Estimate adds the measurement to the window and returns either the measurement or, if it
+// is an outlier, the median of the window.
+// This implements the Filter interface.
+//
+// The median is updated in O(log n); the MAD requires an additional O(n log n) pass over the window.
+func (hf *HampelFilter) Estimate(measurement float64) float64 {
+	hf.window.Push(measurement)
+	median := hf.median.Estimate(measurement)
+
+	hf.scratch = hf.scratch[:0]
+	for v := range hf.window.All() {
+		hf.scratch = append(hf.scratch, math.Abs(v-median))
+	}
+	sigma := max(madScale*sortedMedian(hf.scratch), hf.minDeviation)
+
+	hf.outlier = math.Abs(measurement-median) > hf.threshold*sigma
+	if hf.outlier {
+		return median
+	}
+	return measurement
+}
+
+// IsOutlier returns whether the last measurement passed to Estimate was rejected as an outlier.
+func (hf *HampelFilter) IsOutlier() bool {
+	return hf.outlier
+}
+
+// GetThreshold returns the outlier threshold in scaled median absolute deviations.
+func (hf *HampelFilter) GetThreshold() float64 {
+	return hf.threshold
+}
+
+// SetMinDeviation sets the floor on the scaled median absolute deviation, in the units of the
+// measurement. Samples within threshold times this deviation of the median always pass through.
+// The default of zero applies no floor.
+// Returns an error if the deviation is negative or NaN.
+func (hf *HampelFilter) SetMinDeviation(deviation float64) error {
+	if !(deviation >= 0) {
+		return errors.New("minimum deviation must be non-negative")
+	}
+	hf.minDeviation = deviation
+	return nil
+}
+
+// GetMinDeviation returns the floor on the scaled median absolute deviation.
+func (hf *HampelFilter) GetMinDeviation() float64 {
+	return hf.minDeviation
+}
+
+// GetGain returns the outlier threshold (alias for GetThreshold).
+// This method exists to satisfy the Filter interface.
+func (hf *HampelFilter) GetGain() float64 {
+	return hf.threshold
+}
+
+// Reset clears the window. The next call to Estimate starts a new window.
+func (hf *HampelFilter) Reset() {
+	hf.median.Reset()
+	hf.window.Clear()
+	hf.outlier = false
+}
+
+// sortedMedian sorts values in place and returns their median.
+func sortedMedian(values []float64) float64 {
+	n := len(values)
+	if n == 0 {
+		return 0.0
+	}
+	slices.Sort(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
@@ -0,0 +1,118 @@
+package filter
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestHampelFilter tests the HampelFilter functionality
+func TestHampelFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewHampelFilter(0, 3.0); err == nil {
+			t.Error("Expected error for zero window size")
+		}
+		if _, err := NewHampelFilter(5, -1.0); err == nil {
+			t.Error("Expected error for negative threshold")
+		}
+
+		hf, err := NewHampelFilter(7, 3.0)
+		if err != nil {
+			t.Fatalf("Expected no error for valid parameters, got %v", err)
+		}
+
+		var _ Filter = hf
+		if hf.GetGain() != 3.0 {
+			t.Errorf("Expected gain 3.0, got %f", hf.GetGain())
+		}
+	})
+
+	t.Run("Spike rejection", func(t *testing.T) {
+		hf, _ := NewHampelFilter(7, 3.0)
+		rng := rand.New(rand.NewSource(42))
+
+		for i := range 200 {
+			clean := 10.0 + rng.NormFloat64()*0.1
+			measurement := clean
+			spike := i > 10 && i%25 == 0
+			if spike {
+				measurement = 50.0
+			}
+
+			estimate := hf.Estimate(measurement)
+
+			if spike {
+				if !hf.IsOutlier() {
+					t.Errorf("Step %d: expected spike to be flagged as outlier", i)
+				}
+				if math.Abs(estimate-10.0) > 0.5 {
+					t.Errorf("Step %d: expected spike to be replaced by median near 10, got %f", i, estimate)
+				}
+			} else if i > 10 && estimate != measurement && math.Abs(estimate-10.0) > 0.5 {
+				t.Errorf("Step %d: clean sample %f was distorted to %f", i, measurement, estimate)
+			}
+		}
+	})
+
+	t.Run("Clean samples pass through", func(t *testing.T) {
+		hf, _ := NewHampelFilter(5, 3.0)
+
+		for i, measurement := range []float64{1.0, 2.0, 3.0, 4.0, 5.0, 6.0} {
+			if estimate := hf.Estimate(measurement); estimate != measurement {
+				t.Errorf("Step %d: expected %f to pass through, got %f", i, measurement, estimate)
+			}
+		}
+	})
+
+	t.Run("Minimum deviation on a quantized signal", func(t *testing.T) {
+		// An encoder that steps by a single count: without a floor the MAD of the plateau is
+		// zero and the step is rejected
+		counts := []float64{0, 0, 0, 0, 0, 0, 0, 1, 1, 1}
+
+		hf, _ := NewHampelFilter(7, 3.0)
+		for _, c := range counts[:8] {
+			hf.Estimate(c)
+		}
+		if !hf.IsOutlier() {
+			t.Error("Expected the step to be rejected without a floor")
+		}
+
+		hf, _ = NewHampelFilter(7, 3.0)
+		if err := hf.SetMinDeviation(1.0); err != nil {
+			t.Fatal(err)
+		}
+		for i, c := range counts {
+			if estimate := hf.Estimate(c); estimate != c {
+				t.Errorf("Step %d: expected %f to pass through, got %f", i, c, estimate)
+			}
+		}
+		if estimate := hf.Estimate(50); estimate != 1 || !hf.IsOutlier() {
+			t.Errorf("Expected a spike to still be replaced by the median 1, got %f", estimate)
+		}
+
+		if err := hf.SetMinDeviation(-1); err == nil {
+			t.Error("Expected error for negative minimum deviation")
+		}
+		if err := hf.SetMinDeviation(math.NaN()); err == nil {
+			t.Error("Expected error for NaN minimum deviation")
+		}
+		if hf.GetMinDeviation() != 1.0 {
+			t.Errorf("Expected minimum deviation 1.0, got %f", hf.GetMinDeviation())
+		}
+	})
+
+	t.Run("Reset functionality", func(t *testing.T) {
+		hf, _ := NewHampelFilter(3, 3.0)
+		hf.Estimate(1.0)
+		hf.Estimate(1.0)
+		hf.Estimate(100.0)
+
+		hf.Reset()
+		if hf.IsOutlier() {
+			t.Error("Expected outlier flag to be cleared after reset")
+		}
+		if estimate := hf.Estimate(100.0); estimate != 100.0 {
+			t.Errorf("Expected first estimate after reset 100.0, got %f", estimate)
+		}
+	})
+}
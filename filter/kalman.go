@@ -25,8 +25,10 @@ type KalmanFilter struct {
 	p          float64           // Error covariance estimate
 	k          float64           // Kalman gain
 	x          float64           // State estimate
+	variance   float64           // Error covariance of the latest estimate
 	estimates  *Float64Stack     // Stack of recent estimates
 	regression *LinearRegression // Linear regression for prediction
+	clock      sampleClock       // Timestamps and sample period used by EstimateAt
 }
 
 // NewKalmanFilter creates a new Kalman filter.
@@ -54,16 +56,9 @@ func NewKalmanFilter(q, r float64, n int) (*KalmanFilter, error) {
 	}
 	kf.Reset()
 
-	// Initialize stack with zeros
-	for range n {
-		kf.estimates.Push(0.0)
-	}
-
-	// Initialize regression with the zero-filled stack
-	kf.regression = NewLinearRegression(kf.estimates.ToArray())
-
 	// Calculate initial Kalman gain using DARE
 	kf.findK()
+	kf.variance = kf.p
 
 	return kf, nil
 }
@@ -96,25 +91,78 @@ func (kf *KalmanFilter) GetGain() float64 {
 // Estimate processes a measurement and returns the optimal state estimate.
 // This implements the Filter interface.
 func (kf *KalmanFilter) Estimate(measurement float64) float64 {
-	// Run regression to get prediction
-	kf.regression.RunLeastSquares()
-
 	// Update state estimate using regression prediction
 	prediction := kf.regression.PredictNextValue()
 	kf.x += prediction - kf.estimates.Peek()
 
 	// Apply Kalman filter update
 	kf.x += kf.k * (measurement - kf.x)
+	kf.variance = kf.p
 
-	// Store new estimate
+	// Store new estimate; the regression window drops the oldest estimate in O(1)
 	kf.estimates.Push(kf.x)
+	kf.regression.Push(kf.x)
+
+	return kf.x
+}
+
+// EstimateAt processes a measurement taken at time t, in seconds, and returns the state estimate.
+// This implements the TimedFilter interface.
+//
+// The regression model is extrapolated over the elapsed number of sample periods and the process
+// noise grows with the gap, so the measurement is weighted more heavily after dropped samples.
+// The period is the one set with SetSamplePeriod or, by default, the lower median of the last few
+// intervals. Until a few intervals have arrived that estimate rests on little data, so set the
+// period explicitly if the first samples may be irregular. Measurements at or before the
+// previous timestamp are ignored.
+func (kf *KalmanFilter) EstimateAt(measurement, t float64) float64 {
+	periods, ok := kf.clock.advance(t)
+	if !ok {
+		return kf.x
+	}
+
+	// Extrapolate the regression model over the elapsed periods
+	origin := kf.regression.origin
+	kf.x += kf.regression.Predict(origin+periods) - kf.estimates.Peek()
 
-	// Update regression with new data
-	kf.regression.UpdateData(kf.estimates.ToArray())
+	// The prior covariance grows by the process noise of every elapsed period
+	prior := kf.p + periods*kf.q
+	k := prior / (prior + kf.r)
+	kf.x += k * (measurement - kf.x)
+	kf.variance = (1 - k) * prior
+
+	kf.estimates.Push(kf.x)
+	kf.regression.PushAt(origin+periods, kf.x)
 
 	return kf.x
 }
 
+// Variance returns the error covariance of the latest estimate. It equals the steady-state
+// covariance P, except after a gap in the samples passed to EstimateAt.
+// This implements the VarianceReporter interface.
+func (kf *KalmanFilter) Variance() float64 {
+	return kf.variance
+}
+
+// SetSamplePeriod sets the nominal time between samples, in seconds, used by EstimateAt to
+// account for dropped or irregular samples. Zero restores the default of inferring the period
+// from the recent intervals.
+// Returns an error if the period is negative.
+func (kf *KalmanFilter) SetSamplePeriod(period float64) error {
+	if period < 0 {
+		return errors.New("sample period must be non-negative")
+	}
+	kf.clock.period = period
+	return nil
+}
+
+// GetSamplePeriod returns the nominal time between samples: the period set with
+// SetSamplePeriod, or else the period inferred from the recent intervals passed to EstimateAt, or
+// 0 if neither is known yet.
+func (kf *KalmanFilter) GetSamplePeriod() float64 {
+	return kf.clock.nominal()
+}
+
 // findK iteratively computes the Kalman gain using the DARE.
 func (kf *KalmanFilter) findK() {
 	// Run 2000 iterations to converge to steady-state solution
@@ -145,16 +193,20 @@ func (kf *KalmanFilter) Reset() {
 	// Reset state estimate
 	kf.x = 0.0
 
-	// Reinitialize stack with zeros
-	kf.estimates = NewFloat64Stack(kf.n)
+	// Reinitialize stack and regression window with zeros
+	if kf.regression == nil {
+		kf.regression, _ = NewPolynomialRegression(1, kf.n, 1.0)
+	}
+	kf.estimates.Clear()
+	kf.regression.Reset()
 	for i := 0; i < kf.n; i++ {
 		kf.estimates.Push(0.0)
+		kf.regression.Push(0.0)
 	}
 
-	// Reinitialize regression
-	kf.regression = NewLinearRegression(kf.estimates.ToArray())
-
 	// Restore converged values (they should be the same for the same Q,R parameters)
 	kf.p = convergedP
 	kf.k = convergedK
+	kf.variance = convergedP
+	kf.clock.reset()
 }
@@ -1,82 +1,408 @@
 package filter
 
-import "math"
+import (
+	"errors"
+	"math"
 
-// LinearRegression provides least squares linear regression functionality.
+	"control/linalg"
+)
+
+// ErrReducedDegree is returned by LinearRegression.RunLeastSquares when the points do not
+// determine every coefficient of the requested degree and a lower degree was fitted instead.
+var ErrReducedDegree = errors.New("normal equations are singular, fitted a lower degree")
+
+// LinearRegression provides incremental least squares polynomial regression.
+//
+// Rather than storing the data and recomputing sums on every fit, the regression keeps
+// weighted power sums (sum of w*x^k and w*x^k*y) that are updated in O(1) with respect to the
+// number of points as samples are pushed and popped. This supports:
+//   - a sliding window of the most recent N points,
+//   - exponential forgetting, where each new point scales the weight of older points by lambda,
+//   - explicit x values such as timestamps, or implicit x values 0, 1, 2, ... when using Push,
+//   - polynomial fits of any degree (degree 1 is a straight line).
+//
+// The sums are kept relative to the x value of the newest point, so large x values such as
+// timestamps do not degrade the conditioning of the fit, and the offsets are scaled by their
+// spread before solving, so long windows and high degrees remain well conditioned.
 type LinearRegression struct {
-	data      []float64
-	slope     float64
-	intercept float64
-	hasRun    bool
+	degree     int     // Degree of the fitted polynomial
+	window     int     // Maximum number of points, or 0 for no limit
+	forgetting float64 // Forgetting factor lambda in (0, 1]
+
+	points pointQueue // Points of a windowed regression in arrival order (0 = oldest)
+	count  int        // Number of points in the regression
+	first  float64    // Absolute x of the oldest point
+	origin float64    // Absolute x of the newest point; all sums are relative to it
+	sx     []float64  // Weighted sums of (x-origin)^k for k = 0..2*degree
+	sxy    []float64  // Weighted sums of (x-origin)^k * y for k = 0..degree
+
+	coefficients []float64   // Fitted coefficients relative to origin, lowest power first
+	fitted       int         // Degree of the last fit
+	binomial     [][]float64 // Binomial coefficients used to shift the origin
+	normal       [][]float64 // Scratch space for the normal equations
+	scratch      []float64   // Scratch space for shifting the sums
+	hasRun       bool
 }
 
-// NewLinearRegression creates a new linear regression with the given data.
+// regressionPoint is a single (x, y) sample, with x stored as an absolute value.
+type regressionPoint struct {
+	x, y float64
+}
+
+// NewLinearRegression creates a new straight-line regression with the given data.
+// The data points are assigned the x values 0, 1, 2, ... and there is no window or forgetting.
 func NewLinearRegression(data []float64) *LinearRegression {
-	return &LinearRegression{
-		data: make([]float64, len(data)),
+	lr, _ := NewPolynomialRegression(1, 0, 1.0)
+	lr.UpdateData(data)
+	return lr
+}
+
+// NewPolynomialRegression creates a new, empty incremental regression.
+//
+// Parameters:
+//   - degree: Degree of the fitted polynomial (1 fits a straight line)
+//   - window: Maximum number of points kept; the oldest point is dropped when a new one is
+//     pushed into a full window. Zero keeps every point in the sums without storing them, so
+//     memory use stays constant.
+//   - forgetting: Forgetting factor lambda in (0, 1]. Each push multiplies the weight of the
+//     existing points by lambda; 1 weights every point equally.
+//
+// Returns an error if degree is negative, window is negative or forgetting is out of range.
+func NewPolynomialRegression(degree, window int, forgetting float64) (*LinearRegression, error) {
+	if degree < 0 {
+		return nil, errors.New("degree must be non-negative")
+	}
+	if window < 0 {
+		return nil, errors.New("window size must be non-negative")
+	}
+	if forgetting <= 0 || forgetting > 1 {
+		return nil, errors.New("forgetting factor must be between 0 (exclusive) and 1 (inclusive)")
+	}
+
+	lr := &LinearRegression{
+		degree:       degree,
+		window:       window,
+		forgetting:   forgetting,
+		sx:           make([]float64, 2*degree+1),
+		sxy:          make([]float64, degree+1),
+		coefficients: make([]float64, degree+1),
+		scratch:      make([]float64, 2*degree+1),
+		normal:       make([][]float64, degree+1),
+		binomial:     make([][]float64, 2*degree+1),
+	}
+	for i := range lr.normal {
+		lr.normal[i] = make([]float64, degree+1)
 	}
+	for k := range lr.binomial {
+		lr.binomial[k] = make([]float64, k+1)
+		lr.binomial[k][0] = 1
+		lr.binomial[k][k] = 1
+		for j := 1; j < k; j++ {
+			lr.binomial[k][j] = lr.binomial[k-1][j-1] + lr.binomial[k-1][j]
+		}
+	}
+	if window > 0 {
+		lr.points.buf = make([]regressionPoint, window)
+	}
+
+	return lr, nil
 }
 
-// RunLeastSquares performs least squares regression on the data.
-// Uses the formula: y = mx + b where x represents time indices.
-func (lr *LinearRegression) RunLeastSquares() error {
-	n := len(lr.data)
-	if n < 2 {
-		lr.slope = 0
-		lr.intercept = 0
-		lr.hasRun = true
-		return nil
+// Push adds a point whose x value is one more than the x value of the newest point
+// (or 0 for the first point).
+func (lr *LinearRegression) Push(y float64) {
+	x := 0.0
+	if lr.count > 0 {
+		x = lr.origin + 1
+	}
+	lr.PushAt(x, y)
+}
+
+// PushAt adds a point with an explicit x value, such as a timestamp. If the window is full,
+// the oldest point is removed first.
+func (lr *LinearRegression) PushAt(x, y float64) {
+	if lr.window > 0 && lr.count == lr.window {
+		lr.Pop()
+	}
+
+	if lr.count == 0 {
+		clear(lr.sx)
+		clear(lr.sxy)
+		lr.first = x
+	} else {
+		lr.shift(x - lr.origin)
+		if lr.forgetting != 1 {
+			for k := range lr.sx {
+				lr.sx[k] *= lr.forgetting
+			}
+			for k := range lr.sxy {
+				lr.sxy[k] *= lr.forgetting
+			}
+		}
 	}
+	lr.origin = x
 
-	// Calculate sums for least squares formula
-	var sumX, sumY, sumXY, sumXX float64
+	// The new point is at the origin, so only the zeroth powers change
+	lr.sx[0]++
+	lr.sxy[0] += y
 
-	for i, y := range lr.data {
-		x := float64(i)
-		sumX += x
-		sumY += y
-		sumXY += x * y
-		sumXX += x * x
+	if lr.window > 0 {
+		lr.points.push(regressionPoint{x: x, y: y})
+	}
+	lr.count++
+	lr.hasRun = false
+}
+
+// Pop removes the oldest point of a windowed regression. Only a windowed regression stores its
+// points, so Pop returns false without a window, as well as if there are no points.
+func (lr *LinearRegression) Pop() bool {
+	n := lr.count
+	if lr.window == 0 || n == 0 {
+		return false
 	}
 
-	nf := float64(n)
+	oldest := lr.points.pop()
+	lr.count--
+	lr.hasRun = false
+
+	if n == 1 {
+		clear(lr.sx)
+		clear(lr.sxy)
+		return true
+	}
+	lr.first = lr.points.at(0).x
 
-	// Calculate slope and intercept
-	denominator := nf*sumXX - sumX*sumX
-	if math.Abs(denominator) < 1e-10 {
-		lr.slope = 0
-		if n > 0 {
-			lr.intercept = sumY / nf
-		} else {
-			lr.intercept = 0
+	// The oldest point has been scaled by lambda once for every newer point
+	w := math.Pow(lr.forgetting, float64(n-1))
+	dx := oldest.x - lr.origin
+	p := w
+	for k := range lr.sx {
+		lr.sx[k] -= p
+		if k < len(lr.sxy) {
+			lr.sxy[k] -= p * oldest.y
 		}
-	} else {
-		lr.slope = (nf*sumXY - sumX*sumY) / denominator
-		lr.intercept = (sumY - lr.slope*sumX) / nf
+		p *= dx
 	}
+	return true
+}
+
+// Len returns the number of points in the regression.
+func (lr *LinearRegression) Len() int {
+	return lr.count
+}
+
+// Reset removes all points from the regression.
+func (lr *LinearRegression) Reset() {
+	lr.points.clear()
+	lr.count = 0
+	lr.first = 0
+	lr.origin = 0
+	clear(lr.sx)
+	clear(lr.sxy)
+	clear(lr.coefficients)
+	lr.fitted = 0
+	lr.hasRun = false
+}
 
+// RunLeastSquares fits the polynomial to the current points.
+//
+// With fewer points than coefficients the degree is lowered to one less than the number of
+// points, so a single point gives a constant fit. If the points still do not determine every
+// coefficient, for example because several share an x value, the highest degree that can be
+// determined is fitted and ErrReducedDegree is returned. FittedDegree reports the degree used.
+func (lr *LinearRegression) RunLeastSquares() error {
+	clear(lr.coefficients)
+	lr.fitted = 0
 	lr.hasRun = true
-	return nil
+
+	if lr.count == 0 {
+		return nil
+	}
+
+	limit := min(lr.degree, lr.count-1)
+	for degree := limit; degree >= 0; degree-- {
+		if lr.solve(degree) {
+			lr.fitted = degree
+			if degree < limit {
+				return ErrReducedDegree
+			}
+			return nil
+		}
+	}
+	return ErrReducedDegree
 }
 
-// PredictNextValue predicts the next value in the sequence based on the linear regression.
-func (lr *LinearRegression) PredictNextValue() float64 {
+// FittedDegree returns the degree of the polynomial fitted by the last call to RunLeastSquares,
+// which is lower than the requested degree if the points did not determine every coefficient.
+func (lr *LinearRegression) FittedDegree() int {
 	if !lr.hasRun {
 		lr.RunLeastSquares()
 	}
+	return lr.fitted
+}
 
-	// For single data point, return that value
-	if len(lr.data) == 1 {
-		return lr.data[0]
+// Predict returns the value of the fitted polynomial at x.
+func (lr *LinearRegression) Predict(x float64) float64 {
+	if !lr.hasRun {
+		lr.RunLeastSquares()
 	}
 
-	nextX := float64(len(lr.data))
-	return lr.slope*nextX + lr.intercept
+	dx := x - lr.origin
+	var result float64
+	for k := len(lr.coefficients) - 1; k >= 0; k-- {
+		result = result*dx + lr.coefficients[k]
+	}
+	return result
 }
 
-// UpdateData updates the regression data and marks it as needing to be recalculated.
+// Derivative returns the slope of the fitted polynomial at x.
+func (lr *LinearRegression) Derivative(x float64) float64 {
+	if !lr.hasRun {
+		lr.RunLeastSquares()
+	}
+
+	dx := x - lr.origin
+	var result float64
+	for k := len(lr.coefficients) - 1; k >= 1; k-- {
+		result = result*dx + float64(k)*lr.coefficients[k]
+	}
+	return result
+}
+
+// PredictNextValue predicts the next value in the sequence based on the regression.
+// The next x value is the newest x plus the average spacing between points, which for
+// points added with Push is the next index.
+func (lr *LinearRegression) PredictNextValue() float64 {
+	n := lr.count
+	if n == 0 {
+		return 0.0
+	}
+
+	nextX := lr.origin + 1
+	if n > 1 {
+		nextX = lr.origin + (lr.origin-lr.first)/float64(n-1)
+	}
+	return lr.Predict(nextX)
+}
+
+// Coefficients returns the fitted polynomial coefficients in terms of absolute x values,
+// lowest power first, so that y = c[0] + c[1]*x + c[2]*x^2 + ...
+func (lr *LinearRegression) Coefficients() []float64 {
+	if !lr.hasRun {
+		lr.RunLeastSquares()
+	}
+
+	// Expand sum(a[j] * (x - origin)^j) into powers of x
+	result := make([]float64, len(lr.coefficients))
+	for j, a := range lr.coefficients {
+		p := a
+		for k := j; k >= 0; k-- {
+			result[k] += lr.binomial[j][k] * p
+			p *= -lr.origin
+		}
+	}
+	return result
+}
+
+// UpdateData replaces the regression data with the given values, assigned the x values
+// 0, 1, 2, ..., and marks it as needing to be recalculated.
 func (lr *LinearRegression) UpdateData(data []float64) {
-	lr.data = make([]float64, len(data))
-	copy(lr.data, data)
-	lr.hasRun = false
+	lr.Reset()
+	for _, y := range data {
+		lr.Push(y)
+	}
+}
+
+// pointQueue is a first-in, first-out queue of points backed by a ring buffer. It only
+// grows when full, and a regression with a window sizes it at construction, so it never
+// allocates afterwards.
+type pointQueue struct {
+	buf   []regressionPoint
+	head  int // Index of the oldest point
+	count int // Number of points in the queue
+}
+
+// push adds a point after the newest point, growing the buffer if it is full.
+func (q *pointQueue) push(p regressionPoint) {
+	if q.count == len(q.buf) {
+		buf := make([]regressionPoint, max(2*len(q.buf), 8))
+		for i := range q.count {
+			buf[i] = q.at(i)
+		}
+		q.buf = buf
+		q.head = 0
+	}
+	q.buf[(q.head+q.count)%len(q.buf)] = p
+	q.count++
+}
+
+// pop removes and returns the oldest point. The queue must not be empty.
+func (q *pointQueue) pop() regressionPoint {
+	p := q.buf[q.head]
+	q.head = (q.head + 1) % len(q.buf)
+	q.count--
+	return p
+}
+
+// at returns the point at the given index (0 = oldest).
+func (q *pointQueue) at(index int) regressionPoint {
+	return q.buf[(q.head+index)%len(q.buf)]
+}
+
+// clear removes every point without releasing the buffer.
+func (q *pointQueue) clear() {
+	q.head = 0
+	q.count = 0
+}
+
+// shift moves the origin of the power sums by c, so that each x becomes x - c.
+func (lr *LinearRegression) shift(c float64) {
+	if c == 0 {
+		return
+	}
+
+	shiftSums(lr.sx, lr.scratch, lr.binomial, c)
+	shiftSums(lr.sxy, lr.scratch, lr.binomial, c)
+}
+
+// shiftSums rewrites sums of x^k into sums of (x - c)^k using the binomial expansion.
+func shiftSums(sums, scratch []float64, binomial [][]float64, c float64) {
+	copy(scratch, sums)
+	for k := range sums {
+		var total float64
+		p := 1.0 // (-c)^(k-j)
+		for j := k; j >= 0; j-- {
+			total += binomial[k][j] * p * scratch[j]
+			p *= -c
+		}
+		sums[k] = total
+	}
+}
+
+// solve solves the normal equations for a polynomial of the given degree. Returns false, with
+// the coefficients cleared, if the equations are singular.
+//
+// The raw power sums grow like spread^(2*degree), so the equations are solved for u = dx/scale,
+// where scale is the weighted RMS offset from the origin, and the coefficients converted back.
+func (lr *LinearRegression) solve(degree int) bool {
+	scale := 1.0
+	if lr.sx[0] > 0 && len(lr.sx) > 2 && lr.sx[2] > 0 {
+		scale = math.Sqrt(lr.sx[2] / lr.sx[0])
+	}
+
+	m := degree + 1
+	for i := range m {
+		for j := range m {
+			lr.normal[i][j] = lr.sx[i+j] / math.Pow(scale, float64(i+j))
+		}
+		lr.coefficients[i] = lr.sxy[i] / math.Pow(scale, float64(i))
+	}
+	if err := linalg.SolveInPlace(lr.normal, lr.coefficients[:m]); err != nil {
+		clear(lr.coefficients)
+		return false
+	}
+	for i := range m {
+		lr.coefficients[i] /= math.Pow(scale, float64(i))
+	}
+	return true
 }
@@ -0,0 +1,254 @@
+package filter
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// bruteForceLine fits y = slope*x + intercept with weights by directly summing over the points.
+func bruteForceLine(xs, ys, ws []float64) (slope, intercept float64) {
+	var sw, sx, sy, sxx, sxy float64
+	for i := range xs {
+		sw += ws[i]
+		sx += ws[i] * xs[i]
+		sy += ws[i] * ys[i]
+		sxx += ws[i] * xs[i] * xs[i]
+		sxy += ws[i] * xs[i] * ys[i]
+	}
+	slope = (sw*sxy - sx*sy) / (sw*sxx - sx*sx)
+	intercept = (sy - slope*sx) / sw
+	return slope, intercept
+}
+
+// TestPolynomialRegression tests the incremental regression functionality
+func TestPolynomialRegression(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewPolynomialRegression(-1, 0, 1.0); err == nil {
+			t.Error("Expected error for negative degree")
+		}
+		if _, err := NewPolynomialRegression(1, -1, 1.0); err == nil {
+			t.Error("Expected error for negative window")
+		}
+		if _, err := NewPolynomialRegression(1, 0, 0.0); err == nil {
+			t.Error("Expected error for zero forgetting factor")
+		}
+		if _, err := NewPolynomialRegression(1, 0, 1.5); err == nil {
+			t.Error("Expected error for forgetting factor above 1")
+		}
+	})
+
+	t.Run("Sliding window matches brute force", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(3))
+		lr, _ := NewPolynomialRegression(1, 10, 1.0)
+
+		var xs, ys []float64
+		x := 0.0
+		for i := range 300 {
+			x += 0.01 + rng.Float64()*0.02
+			y := 3*x - 1 + rng.NormFloat64()*0.1
+			lr.PushAt(x, y)
+			xs = append(xs, x)
+			ys = append(ys, y)
+
+			if i < 2 {
+				continue
+			}
+			start := max(0, len(xs)-10)
+			ws := make([]float64, len(xs)-start)
+			for j := range ws {
+				ws[j] = 1
+			}
+			slope, intercept := bruteForceLine(xs[start:], ys[start:], ws)
+
+			if got := lr.Derivative(x); math.Abs(got-slope) > 1e-6 {
+				t.Fatalf("Step %d: expected slope %f, got %f", i, slope, got)
+			}
+			if got := lr.Predict(x); math.Abs(got-(slope*x+intercept)) > 1e-6 {
+				t.Fatalf("Step %d: expected prediction %f, got %f", i, slope*x+intercept, got)
+			}
+		}
+
+		if lr.Len() != 10 {
+			t.Errorf("Expected window of 10 points, got %d", lr.Len())
+		}
+	})
+
+	t.Run("Forgetting factor matches weighted fit", func(t *testing.T) {
+		lambda := 0.8
+		lr, _ := NewPolynomialRegression(1, 6, lambda)
+
+		ys := []float64{1.0, 4.0, 2.0, 8.0, 5.0, 7.0}
+		xs := make([]float64, len(ys))
+		ws := make([]float64, len(ys))
+		for i, y := range ys {
+			lr.Push(y)
+			xs[i] = float64(i)
+			ws[i] = math.Pow(lambda, float64(len(ys)-1-i))
+		}
+
+		slope, intercept := bruteForceLine(xs, ys, ws)
+		coefficients := lr.Coefficients()
+		if math.Abs(coefficients[0]-intercept) > 1e-9 || math.Abs(coefficients[1]-slope) > 1e-9 {
+			t.Errorf("Expected (%f, %f), got (%f, %f)", intercept, slope, coefficients[0], coefficients[1])
+		}
+
+		// Popping with forgetting removes the correctly decayed weight
+		lr.Pop()
+		slope, intercept = bruteForceLine(xs[1:], ys[1:], ws[1:])
+		if got := lr.Predict(10.0); math.Abs(got-(slope*10+intercept)) > 1e-9 {
+			t.Errorf("Expected %f after pop, got %f", slope*10+intercept, got)
+		}
+	})
+
+	t.Run("Quadratic fit with timestamps", func(t *testing.T) {
+		lr, _ := NewPolynomialRegression(2, 50, 1.0)
+
+		// Large absolute timestamps would ruin the conditioning of raw power sums
+		t0 := 1.7e9
+		for i := range 200 {
+			dt := float64(i) * 0.01
+			lr.PushAt(t0+dt, 2-0.5*dt+3*dt*dt)
+		}
+
+		dt := 2.0
+		expected := 2 - 0.5*dt + 3*dt*dt
+		if got := lr.Predict(t0 + dt); math.Abs(got-expected) > 1e-6 {
+			t.Errorf("Expected prediction %f, got %f", expected, got)
+		}
+		if got := lr.Derivative(t0 + dt); math.Abs(got-(-0.5+6*dt)) > 1e-4 {
+			t.Errorf("Expected derivative %f, got %f", -0.5+6*dt, got)
+		}
+	})
+
+	t.Run("Coefficients in absolute x", func(t *testing.T) {
+		lr, _ := NewPolynomialRegression(2, 0, 1.0)
+		for x := 5.0; x < 12; x++ {
+			lr.PushAt(x, 1+2*x-0.5*x*x)
+		}
+
+		expected := []float64{1, 2, -0.5}
+		for i, c := range lr.Coefficients() {
+			if math.Abs(c-expected[i]) > 1e-9 {
+				t.Errorf("Expected coefficient[%d] = %f, got %f", i, expected[i], c)
+			}
+		}
+	})
+
+	t.Run("Degree falls back with too few points", func(t *testing.T) {
+		lr, _ := NewPolynomialRegression(3, 0, 1.0)
+		lr.PushAt(1.0, 4.0)
+		if got := lr.Predict(10.0); got != 4.0 {
+			t.Errorf("Expected constant fit 4.0, got %f", got)
+		}
+
+		lr.PushAt(2.0, 6.0)
+		if got := lr.Predict(3.0); math.Abs(got-8.0) > 1e-9 {
+			t.Errorf("Expected linear fit 8.0, got %f", got)
+		}
+
+		// Repeated x values cannot determine a slope, which is reported
+		lr.Reset()
+		lr.PushAt(1.0, 2.0)
+		lr.PushAt(1.0, 4.0)
+		if err := lr.RunLeastSquares(); !errors.Is(err, ErrReducedDegree) {
+			t.Errorf("Expected ErrReducedDegree, got %v", err)
+		}
+		if got := lr.FittedDegree(); got != 0 {
+			t.Errorf("Expected fitted degree 0, got %d", got)
+		}
+		if got := lr.Predict(5.0); math.Abs(got-3.0) > 1e-9 {
+			t.Errorf("Expected mean 3.0, got %f", got)
+		}
+	})
+
+	t.Run("Cubic fit over a long span", func(t *testing.T) {
+		// The raw power sums reach 1e27 here, far beyond the pivot tolerance of the solver
+		cubic := func(x float64) float64 {
+			return 5 - 0.5*x + 3e-4*x*x + 2e-8*x*x*x
+		}
+		lr, _ := NewPolynomialRegression(3, 0, 1.0)
+		for i := range 10000 {
+			lr.Push(cubic(float64(i)))
+		}
+		if err := lr.RunLeastSquares(); err != nil {
+			t.Fatal(err)
+		}
+		if got := lr.FittedDegree(); got != 3 {
+			t.Errorf("Expected fitted degree 3, got %d", got)
+		}
+		for _, x := range []float64{0, 2500, 9999, 10500} {
+			expected := cubic(x)
+			if got := lr.Predict(x); math.Abs(got-expected) > 1e-6*math.Abs(expected) {
+				t.Errorf("Predict(%f) = %f, expected %f", x, got, expected)
+			}
+		}
+	})
+
+	t.Run("Pop and reset", func(t *testing.T) {
+		lr, _ := NewPolynomialRegression(1, 10, 1.0)
+		if lr.Pop() {
+			t.Error("Expected Pop on empty regression to return false")
+		}
+
+		lr.UpdateData([]float64{0.0, 100.0, 2.0, 4.0})
+		lr.Pop()
+		lr.Pop()
+		if got := lr.PredictNextValue(); math.Abs(got-6.0) > 1e-9 {
+			t.Errorf("Expected 6.0 after popping, got %f", got)
+		}
+
+		lr.Reset()
+		if lr.Len() != 0 || lr.PredictNextValue() != 0.0 {
+			t.Error("Expected empty regression after reset")
+		}
+	})
+
+	t.Run("Unwindowed storage stays bounded", func(t *testing.T) {
+		lr, _ := NewPolynomialRegression(1, 0, 0.99)
+		for i := range 100000 {
+			lr.Push(3 + 0.5*float64(i))
+		}
+		if lr.Len() != 100000 {
+			t.Errorf("Expected 100000 points, got %d", lr.Len())
+		}
+		if len(lr.points.buf) != 0 {
+			t.Errorf("Expected no stored points without a window, got %d", len(lr.points.buf))
+		}
+		if got := lr.PredictNextValue(); math.Abs(got-(3+0.5*100000)) > 1e-6 {
+			t.Errorf("Expected %f, got %f", 3+0.5*100000, got)
+		}
+		if lr.Pop() {
+			t.Error("Expected Pop without a window to return false")
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			lr.Push(1)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected no allocations, got %f", allocs)
+		}
+	})
+
+	t.Run("Constructor uses data", func(t *testing.T) {
+		lr := NewLinearRegression([]float64{1.0, 3.0, 5.0})
+		if got := lr.PredictNextValue(); math.Abs(got-7.0) > 1e-9 {
+			t.Errorf("Expected 7.0, got %f", got)
+		}
+	})
+}
+
+// BenchmarkLinearRegressionPush benchmarks pushing into a windowed regression and predicting
+func BenchmarkLinearRegressionPush(b *testing.B) {
+	lr, err := NewPolynomialRegression(1, 100, 1.0)
+	if err != nil {
+		b.Fatalf("Failed to create regression: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lr.Push(float64(i % 100))
+		lr.PredictNextValue()
+	}
+}
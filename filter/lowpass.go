@@ -2,6 +2,7 @@ package filter
 
 import (
 	"errors"
+	"math"
 )
 
 // LowPassFilter implements a simple first-order low-pass filter.
@@ -12,9 +13,10 @@ import (
 // High values of alpha (closer to 1) are smoother but have more phase lag.
 // Low values of alpha (closer to 0) allow more noise but respond faster to changes.
 type LowPassFilter struct {
-	alpha            float64 // Filter alpha (0 < alpha < 1)
-	previousEstimate float64 // Previous filtered value
-	initialized      bool    // Whether the filter has been initialized
+	alpha            float64     // Filter alpha (0 < alpha < 1)
+	previousEstimate float64     // Previous filtered value
+	initialized      bool        // Whether the filter has been initialized
+	clock            sampleClock // Timestamps and sample period used by EstimateAt
 }
 
 // NewLowPassFilter creates a new low-pass filter with the specified alpha.
@@ -41,6 +43,52 @@ func NewLowPassFilter(alpha float64) (*LowPassFilter, error) {
 // On the first call, the filter is initialized with the measurement value.
 // Subsequent calls apply the low-pass filtering formula.
 func (lpf *LowPassFilter) Estimate(measurement float64) float64 {
+	return lpf.estimate(measurement, lpf.alpha)
+}
+
+// EstimateAll processes each measurement in order as if passed to Estimate, writing the
+// estimates to out, which must be at least as long as in. in and out may be the same slice.
+// The estimate stays in a local variable for the whole slice rather than being stored after
+// every sample.
+// This implements the BatchFilter interface.
+func (lpf *LowPassFilter) EstimateAll(in, out []float64) {
+	out = out[:len(in)]
+	if len(in) == 0 {
+		return
+	}
+	if !lpf.initialized {
+		out[0] = lpf.Estimate(in[0])
+		in, out = in[1:], out[1:]
+	}
+
+	// Keep the estimate in a local variable so the loop does not write to memory each sample
+	alpha, estimate := lpf.alpha, lpf.previousEstimate
+	for i, measurement := range in {
+		estimate = alpha*estimate + (1-alpha)*measurement
+		out[i] = estimate
+	}
+	lpf.previousEstimate = estimate
+}
+
+// EstimateAt processes a measurement taken at time t, in seconds.
+// This implements the TimedFilter interface.
+//
+// Alpha is applied once per elapsed sample period, so a gap of several periods moves the
+// estimate as far towards the measurement as the missing samples would have. The period is the
+// one set with SetSamplePeriod or, by default, the lower median of the last few intervals. Until
+// a few intervals have arrived that estimate rests on little data, so set the period explicitly
+// if the first samples may be irregular. Measurements at or before the previous timestamp are
+// ignored.
+func (lpf *LowPassFilter) EstimateAt(measurement, t float64) float64 {
+	periods, ok := lpf.clock.advance(t)
+	if !ok {
+		return lpf.previousEstimate
+	}
+	return lpf.estimate(measurement, math.Pow(lpf.alpha, periods))
+}
+
+// estimate applies the low-pass filter with the given alpha.
+func (lpf *LowPassFilter) estimate(measurement, alpha float64) float64 {
 	if !lpf.initialized {
 		// Initialize with first measurement
 		lpf.previousEstimate = measurement
@@ -49,7 +97,7 @@ func (lpf *LowPassFilter) Estimate(measurement float64) float64 {
 	}
 
 	// Apply low-pass filter: estimate = alpha * previous + (1-alpha) * measurement
-	estimate := lpf.alpha*lpf.previousEstimate + (1-lpf.alpha)*measurement
+	estimate := alpha*lpf.previousEstimate + (1-alpha)*measurement
 	lpf.previousEstimate = estimate
 	return estimate
 }
@@ -75,11 +123,31 @@ func (lpf *LowPassFilter) SetAlpha(alpha float64) error {
 	return nil
 }
 
+// SetSamplePeriod sets the nominal time between samples, in seconds, that alpha applies to.
+// It is used by EstimateAt to account for dropped or irregular samples. Zero restores the
+// default of inferring the period from the recent intervals.
+// Returns an error if the period is negative.
+func (lpf *LowPassFilter) SetSamplePeriod(period float64) error {
+	if period < 0 {
+		return errors.New("sample period must be non-negative")
+	}
+	lpf.clock.period = period
+	return nil
+}
+
+// GetSamplePeriod returns the nominal time between samples: the period set with
+// SetSamplePeriod, or else the period inferred from the recent intervals passed to EstimateAt, or
+// 0 if neither is known yet.
+func (lpf *LowPassFilter) GetSamplePeriod() float64 {
+	return lpf.clock.nominal()
+}
+
 // Reset resets the filter to its uninitialized state.
 // The next call to Estimate will initialize the filter with the provided measurement.
 func (lpf *LowPassFilter) Reset() {
 	lpf.previousEstimate = 0.0
 	lpf.initialized = false
+	lpf.clock.reset()
 }
 
 // GetLastEstimate returns the last filtered value.
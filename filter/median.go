@@ -0,0 +1,164 @@
+package filter
+
+import (
+	"container/heap"
+	"errors"
+)
+
+// MedianFilter implements a sliding-window median filter.
+//
+// Unlike the LowPassFilter, which smears a single spike across several samples, the
+// median filter removes isolated spikes entirely as long as they occupy less than half
+// of the window. The window is kept in a SizedStack in arrival order, while the values
+// are split across two heaps (the lower half in a max-heap, the upper half in a min-heap)
+// so each new sample is processed in O(log n).
+type MedianFilter struct {
+	size   int                      // Number of samples in the window
+	window *SizedStack[*medianNode] // Samples in arrival order (0 = oldest)
+	lower  *medianHeap              // Max-heap holding the lower half of the window
+	upper  *medianHeap              // Min-heap holding the upper half of the window
+}
+
+// NewMedianFilter creates a new median filter over the most recent size samples.
+//
+// Parameters:
+//   - size: Number of samples in the sliding window. Odd sizes give a true median.
+//
+// Returns an error if size is not positive.
+func NewMedianFilter(size int) (*MedianFilter, error) {
+	if size <= 0 {
+		return nil, errors.New("window size must be positive")
+	}
+
+	mf := &MedianFilter{
+		size: size,
+	}
+	mf.Reset()
+
+	return mf, nil
+}
+
+// Estimate adds the measurement to the window and returns the median of the window.
+// This implements the Filter interface.
+//
+// Until the window is full, the median of the samples received so far is returned.
+func (mf *MedianFilter) Estimate(measurement float64) float64 {
+	// Evict the oldest sample before the stack overwrites it, reusing its node
+	var node *medianNode
+	if mf.window.IsFull() {
+		node = mf.window.Oldest()
+		if node.upper {
+			heap.Remove(mf.upper, node.index)
+		} else {
+			heap.Remove(mf.lower, node.index)
+		}
+	} else {
+		node = &medianNode{}
+	}
+
+	node.value = measurement
+	mf.window.Push(node)
+
+	if mf.lower.Len() == 0 || measurement <= mf.lower.top() {
+		heap.Push(mf.lower, node)
+	} else {
+		heap.Push(mf.upper, node)
+	}
+	mf.rebalance()
+
+	return mf.Median()
+}
+
+// Median returns the median of the samples currently in the window without adding a new one.
+// Returns 0.0 if the filter hasn't received any measurements.
+func (mf *MedianFilter) Median() float64 {
+	switch {
+	case mf.lower.Len() == 0:
+		return 0.0
+	case mf.lower.Len() > mf.upper.Len():
+		return mf.lower.top()
+	default:
+		return (mf.lower.top() + mf.upper.top()) / 2
+	}
+}
+
+// GetGain returns 0, as the median filter is nonlinear and has no gain.
+// This method exists to satisfy the Filter interface.
+func (mf *MedianFilter) GetGain() float64 {
+	return 0.0
+}
+
+// GetSize returns the size of the sliding window.
+func (mf *MedianFilter) GetSize() int {
+	return mf.size
+}
+
+// Reset clears the window. The next call to Estimate starts a new window.
+func (mf *MedianFilter) Reset() {
+	if mf.window == nil {
+		mf.window = NewSizedStack[*medianNode](mf.size)
+		mf.lower = &medianHeap{less: func(a, b float64) bool { return a > b }}
+		mf.upper = &medianHeap{less: func(a, b float64) bool { return a < b }, upper: true}
+		return
+	}
+
+	mf.window.Clear()
+	clear(mf.lower.nodes)
+	clear(mf.upper.nodes)
+	mf.lower.nodes = mf.lower.nodes[:0]
+	mf.upper.nodes = mf.upper.nodes[:0]
+}
+
+// rebalance keeps the lower heap the same size as, or one larger than, the upper heap.
+func (mf *MedianFilter) rebalance() {
+	for mf.lower.Len() > mf.upper.Len()+1 {
+		heap.Push(mf.upper, heap.Pop(mf.lower))
+	}
+	for mf.upper.Len() > mf.lower.Len() {
+		heap.Push(mf.lower, heap.Pop(mf.upper))
+	}
+}
+
+// medianNode is a single sample in the median window. It records its position in the
+// heap that holds it so it can be removed in O(log n) when it leaves the window.
+type medianNode struct {
+	value float64
+	index int  // Index of the node in its heap
+	upper bool // Whether the node is held by the upper heap
+}
+
+// medianHeap is a heap of medianNodes ordered by less. It implements heap.Interface.
+type medianHeap struct {
+	nodes []*medianNode
+	less  func(a, b float64) bool
+	upper bool // Whether this is the upper (min) heap
+}
+
+func (h *medianHeap) Len() int           { return len(h.nodes) }
+func (h *medianHeap) Less(i, j int) bool { return h.less(h.nodes[i].value, h.nodes[j].value) }
+
+func (h *medianHeap) Swap(i, j int) {
+	h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i]
+	h.nodes[i].index = i
+	h.nodes[j].index = j
+}
+
+func (h *medianHeap) Push(x any) {
+	node := x.(*medianNode)
+	node.index = len(h.nodes)
+	node.upper = h.upper
+	h.nodes = append(h.nodes, node)
+}
+
+func (h *medianHeap) Pop() any {
+	n := len(h.nodes)
+	node := h.nodes[n-1]
+	h.nodes[n-1] = nil
+	h.nodes = h.nodes[:n-1]
+	return node
+}
+
+// top returns the value at the root of the heap.
+func (h *medianHeap) top() float64 {
+	return h.nodes[0].value
+}
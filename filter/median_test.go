@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// TestMedianFilter tests the MedianFilter functionality
+func TestMedianFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewMedianFilter(0); err == nil {
+			t.Error("Expected error for zero window size")
+		}
+		if _, err := NewMedianFilter(-3); err == nil {
+			t.Error("Expected error for negative window size")
+		}
+
+		mf, err := NewMedianFilter(5)
+		if err != nil {
+			t.Fatalf("Expected no error for valid window size, got %v", err)
+		}
+
+		var _ Filter = mf
+		if mf.GetSize() != 5 {
+			t.Errorf("Expected size 5, got %d", mf.GetSize())
+		}
+	})
+
+	t.Run("Partial window", func(t *testing.T) {
+		mf, _ := NewMedianFilter(5)
+
+		expected := []float64{4.0, 3.0, 4.0, 3.0}
+		for i, measurement := range []float64{4.0, 2.0, 9.0, 1.0} {
+			if estimate := mf.Estimate(measurement); estimate != expected[i] {
+				t.Errorf("Step %d: expected %f, got %f", i, expected[i], estimate)
+			}
+		}
+	})
+
+	t.Run("Spike removal", func(t *testing.T) {
+		mf, _ := NewMedianFilter(3)
+
+		measurements := []float64{1.0, 1.0, 100.0, 1.0, 1.0, -50.0, 1.0}
+		for i, measurement := range measurements {
+			estimate := mf.Estimate(measurement)
+			if i >= 2 && estimate != 1.0 {
+				t.Errorf("Step %d: expected spike to be removed, got %f", i, estimate)
+			}
+		}
+	})
+
+	t.Run("Matches brute force", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+
+		for _, size := range []int{1, 2, 5, 8} {
+			mf, _ := NewMedianFilter(size)
+			var history []float64
+
+			for i := range 500 {
+				measurement := math.Round(rng.NormFloat64() * 10)
+				history = append(history, measurement)
+				start := max(0, len(history)-size)
+
+				window := slices.Clone(history[start:])
+				expected := sortedMedian(window)
+
+				if estimate := mf.Estimate(measurement); estimate != expected {
+					t.Fatalf("Size %d step %d: expected %f, got %f", size, i, expected, estimate)
+				}
+			}
+		}
+	})
+
+	t.Run("Reset functionality", func(t *testing.T) {
+		mf, _ := NewMedianFilter(3)
+		mf.Estimate(10.0)
+		mf.Estimate(20.0)
+
+		mf.Reset()
+		if mf.Median() != 0.0 {
+			t.Errorf("Expected median 0.0 after reset, got %f", mf.Median())
+		}
+		if estimate := mf.Estimate(3.0); estimate != 3.0 {
+			t.Errorf("Expected first estimate after reset 3.0, got %f", estimate)
+		}
+	})
+}
+
+// BenchmarkMedianFilter benchmarks the median filter performance
+func BenchmarkMedianFilter(b *testing.B) {
+	mf, err := NewMedianFilter(31)
+	if err != nil {
+		b.Fatalf("Failed to create median filter: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mf.Estimate(float64(i % 100))
+	}
+}
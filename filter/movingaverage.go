@@ -0,0 +1,178 @@
+package filter
+
+import (
+	"errors"
+)
+
+// MovingAverageFilter implements a simple moving average over the most recent N samples.
+//
+// A running sum is maintained as samples enter and leave the window, so each new sample
+// is processed in O(1) regardless of the window size.
+type MovingAverageFilter struct {
+	size   int           // Number of samples in the window
+	window *Float64Stack // Samples in arrival order (0 = oldest)
+	sum    float64       // Running sum of the samples in the window
+}
+
+// NewMovingAverageFilter creates a new simple moving average filter.
+//
+// Parameters:
+//   - size: Number of samples to average over
+//
+// Returns an error if size is not positive.
+func NewMovingAverageFilter(size int) (*MovingAverageFilter, error) {
+	if size <= 0 {
+		return nil, errors.New("window size must be positive")
+	}
+
+	return &MovingAverageFilter{
+		size:   size,
+		window: NewFloat64Stack(size),
+	}, nil
+}
+
+// Estimate adds the measurement to the window and returns the average of the window.
+// This implements the Filter interface.
+//
+// Until the window is full, the average of the samples received so far is returned.
+func (maf *MovingAverageFilter) Estimate(measurement float64) float64 {
+	if maf.window.Size() == maf.size {
+		maf.sum -= maf.window.Oldest()
+	}
+	maf.window.Push(measurement)
+	maf.sum += measurement
+
+	return maf.sum / float64(maf.window.Size())
+}
+
+// Variance returns the estimated variance of the average, the sample variance of the window
+// divided by the number of samples. Returns 0 if the window holds fewer than two samples.
+// This implements the VarianceReporter interface.
+func (maf *MovingAverageFilter) Variance() float64 {
+	n := maf.window.Size()
+	if n < 2 {
+		return 0.0
+	}
+	// Convert the population variance to the unbiased sample variance, then divide by n
+	return StackVariance(maf.window) / float64(n-1)
+}
+
+// GetGain returns the weight given to the newest sample once the window is full (1/N).
+// This method exists to satisfy the Filter interface.
+func (maf *MovingAverageFilter) GetGain() float64 {
+	return 1.0 / float64(maf.size)
+}
+
+// GetSize returns the size of the averaging window.
+func (maf *MovingAverageFilter) GetSize() int {
+	return maf.size
+}
+
+// Reset clears the window. The next call to Estimate starts a new average.
+func (maf *MovingAverageFilter) Reset() {
+	maf.window.Clear()
+	maf.sum = 0.0
+}
+
+// WeightedMovingAverageFilter implements a weighted moving average over the most recent N samples.
+//
+// Each sample in the window is multiplied by its corresponding weight, and the result is
+// normalized by the sum of the weights in use. Weights are ordered from the oldest sample
+// to the newest, so increasing weights favor recent samples and reduce lag.
+type WeightedMovingAverageFilter struct {
+	weights []float64     // Weights from oldest to newest sample
+	window  *Float64Stack // Samples in arrival order (0 = oldest)
+}
+
+// NewWeightedMovingAverageFilter creates a new weighted moving average filter.
+//
+// Parameters:
+//   - weights: Weights applied to the window, ordered from the oldest sample to the newest.
+//     The window size is the number of weights.
+//
+// Returns an error if no weights are given, any weight is negative, or all weights are zero.
+func NewWeightedMovingAverageFilter(weights []float64) (*WeightedMovingAverageFilter, error) {
+	if len(weights) == 0 {
+		return nil, errors.New("at least one weight is required")
+	}
+
+	var sum float64
+	for _, w := range weights {
+		if w < 0 {
+			return nil, errors.New("weights must be non-negative")
+		}
+		sum += w
+	}
+	if sum == 0 {
+		return nil, errors.New("weights must not all be zero")
+	}
+
+	wmaf := &WeightedMovingAverageFilter{
+		weights: make([]float64, len(weights)),
+		window:  NewFloat64Stack(len(weights)),
+	}
+	copy(wmaf.weights, weights)
+
+	return wmaf, nil
+}
+
+// NewLinearWeightedMovingAverageFilter creates a weighted moving average filter whose weights
+// increase linearly from 1 for the oldest sample to size for the newest.
+//
+// Returns an error if size is not positive.
+func NewLinearWeightedMovingAverageFilter(size int) (*WeightedMovingAverageFilter, error) {
+	if size <= 0 {
+		return nil, errors.New("window size must be positive")
+	}
+
+	weights := make([]float64, size)
+	for i := range weights {
+		weights[i] = float64(i + 1)
+	}
+	return NewWeightedMovingAverageFilter(weights)
+}
+
+// Estimate adds the measurement to the window and returns the weighted average of the window.
+// This implements the Filter interface.
+//
+// Until the window is full, the newest weights are applied to the samples received so far.
+func (wmaf *WeightedMovingAverageFilter) Estimate(measurement float64) float64 {
+	wmaf.window.Push(measurement)
+
+	n := wmaf.window.Size()
+	offset := len(wmaf.weights) - n
+
+	var sum, weightSum float64
+	i := offset
+	for v := range wmaf.window.All() {
+		w := wmaf.weights[i]
+		sum += w * v
+		weightSum += w
+		i++
+	}
+
+	if weightSum == 0 {
+		return measurement
+	}
+	return sum / weightSum
+}
+
+// GetGain returns the normalized weight given to the newest sample once the window is full.
+// This method exists to satisfy the Filter interface.
+func (wmaf *WeightedMovingAverageFilter) GetGain() float64 {
+	var weightSum float64
+	for _, w := range wmaf.weights {
+		weightSum += w
+	}
+	return wmaf.weights[len(wmaf.weights)-1] / weightSum
+}
+
+// GetSize returns the size of the averaging window.
+func (wmaf *WeightedMovingAverageFilter) GetSize() int {
+	return len(wmaf.weights)
+}
+
+// Reset clears the window. The next call to Estimate starts a new average.
+func (wmaf *WeightedMovingAverageFilter) Reset() {
+	wmaf.window.Clear()
+}
@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMovingAverageFilter tests the MovingAverageFilter functionality
+func TestMovingAverageFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewMovingAverageFilter(0); err == nil {
+			t.Error("Expected error for zero window size")
+		}
+
+		maf, err := NewMovingAverageFilter(4)
+		if err != nil {
+			t.Fatalf("Expected no error for valid window size, got %v", err)
+		}
+
+		var _ Filter = maf
+		if maf.GetGain() != 0.25 {
+			t.Errorf("Expected gain 0.25, got %f", maf.GetGain())
+		}
+	})
+
+	t.Run("Averaging", func(t *testing.T) {
+		maf, _ := NewMovingAverageFilter(3)
+
+		measurements := []float64{3.0, 6.0, 9.0, 12.0, 0.0}
+		expected := []float64{3.0, 4.5, 6.0, 9.0, 7.0}
+
+		for i, measurement := range measurements {
+			if estimate := maf.Estimate(measurement); math.Abs(estimate-expected[i]) > 1e-12 {
+				t.Errorf("Step %d: expected %f, got %f", i, expected[i], estimate)
+			}
+		}
+	})
+
+	t.Run("Reset functionality", func(t *testing.T) {
+		maf, _ := NewMovingAverageFilter(3)
+		maf.Estimate(100.0)
+		maf.Estimate(200.0)
+
+		maf.Reset()
+		if estimate := maf.Estimate(1.0); estimate != 1.0 {
+			t.Errorf("Expected first estimate after reset 1.0, got %f", estimate)
+		}
+	})
+}
+
+// TestWeightedMovingAverageFilter tests the WeightedMovingAverageFilter functionality
+func TestWeightedMovingAverageFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewWeightedMovingAverageFilter(nil); err == nil {
+			t.Error("Expected error for empty weights")
+		}
+		if _, err := NewWeightedMovingAverageFilter([]float64{1.0, -1.0}); err == nil {
+			t.Error("Expected error for negative weight")
+		}
+		if _, err := NewWeightedMovingAverageFilter([]float64{0.0, 0.0}); err == nil {
+			t.Error("Expected error for all-zero weights")
+		}
+		if _, err := NewLinearWeightedMovingAverageFilter(0); err == nil {
+			t.Error("Expected error for zero window size")
+		}
+	})
+
+	t.Run("Weighted averaging", func(t *testing.T) {
+		wmaf, _ := NewLinearWeightedMovingAverageFilter(3)
+		var _ Filter = wmaf
+
+		// Weights are 1, 2, 3 from oldest to newest
+		measurements := []float64{6.0, 3.0, 0.0, 6.0}
+		expected := []float64{
+			6.0,                         // 3*6 / 3
+			(2*6.0 + 3*3.0) / 5,         // partial window uses the newest weights
+			(1*6.0 + 2*3.0 + 3*0.0) / 6, // full window
+			(1*3.0 + 2*0.0 + 3*6.0) / 6, // oldest sample dropped
+		}
+
+		for i, measurement := range measurements {
+			if estimate := wmaf.Estimate(measurement); math.Abs(estimate-expected[i]) > 1e-12 {
+				t.Errorf("Step %d: expected %f, got %f", i, expected[i], estimate)
+			}
+		}
+
+		if gain := wmaf.GetGain(); math.Abs(gain-0.5) > 1e-12 {
+			t.Errorf("Expected gain 0.5, got %f", gain)
+		}
+	})
+
+	t.Run("Weights are copied", func(t *testing.T) {
+		weights := []float64{1.0, 1.0}
+		wmaf, _ := NewWeightedMovingAverageFilter(weights)
+		weights[1] = 100.0
+
+		wmaf.Estimate(0.0)
+		if estimate := wmaf.Estimate(2.0); estimate != 1.0 {
+			t.Errorf("Expected 1.0, got %f", estimate)
+		}
+	})
+}
+
+// BenchmarkMovingAverageFilter benchmarks the moving average filter performance
+func BenchmarkMovingAverageFilter(b *testing.B) {
+	maf, err := NewMovingAverageFilter(31)
+	if err != nil {
+		b.Fatalf("Failed to create moving average filter: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		maf.Estimate(float64(i % 100))
+	}
+}
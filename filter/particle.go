@@ -0,0 +1,337 @@
+package filter
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// ErrDegenerateWeights is returned by ParticleFilter.Update when the likelihood of every particle is zero.
+var ErrDegenerateWeights = errors.New("all particle weights are zero")
+
+// ErrInvalidLikelihood is returned by ParticleFilter.Update when the likelihood function returns
+// a negative or NaN value.
+var ErrInvalidLikelihood = errors.New("likelihood must be non-negative")
+
+// MotionModel propagates a particle's state in place over a time step of dt seconds. Process
+// noise should be drawn from rng so that runs with the same seed are reproducible.
+type MotionModel func(state []float64, dt float64, rng *rand.Rand)
+
+// LikelihoodFunc returns the likelihood, up to a constant factor, of the measurement given a
+// particle's state. It must be non-negative; Update returns ErrInvalidLikelihood for a negative
+// or NaN value.
+type LikelihoodFunc func(state, measurement []float64) float64
+
+// ResamplingMethod selects the algorithm used to resample the particles.
+type ResamplingMethod int
+
+const (
+	// SystematicResampling draws a single random offset and selects particles at evenly spaced
+	// points of the cumulative weights. It is fast and has the lowest resampling variance.
+	SystematicResampling ResamplingMethod = iota
+
+	// StratifiedResampling draws an independent random point within each of N equal strata
+	// of the cumulative weights.
+	StratifiedResampling
+)
+
+// ParticleFilter implements a sequential importance resampling (SIR) particle filter.
+//
+// The state distribution is represented by a set of weighted particles, so arbitrary,
+// multimodal and non-Gaussian distributions can be tracked. Predict moves every particle
+// with the user-supplied motion model and Update multiplies each weight by the likelihood of
+// the measurement. When the effective sample size falls below a threshold the particles are
+// resampled in proportion to their weights.
+type ParticleFilter struct {
+	motion     MotionModel      // Propagates particles in Predict
+	likelihood LikelihoodFunc   // Weights particles in Update
+	method     ResamplingMethod // Resampling algorithm
+	threshold  float64          // Resample when ESS < threshold * N
+	rng        *rand.Rand       // Source of randomness for motion and resampling
+
+	dim        int         // Dimension of the state
+	particles  [][]float64 // Particle states
+	weights    []float64   // Normalized particle weights
+	scratch    [][]float64 // Particle storage reused by resampling
+	cumulative []float64   // Cumulative weights reused by resampling
+}
+
+// ParticleFilterOption is a function that configures a ParticleFilter.
+type ParticleFilterOption func(*ParticleFilter)
+
+// WithResampling sets the resampling method. The default is SystematicResampling.
+func WithResampling(method ResamplingMethod) ParticleFilterOption {
+	return func(pf *ParticleFilter) {
+		pf.method = method
+	}
+}
+
+// WithResampleThreshold sets the fraction of the number of particles below which the
+// effective sample size triggers resampling. Zero disables automatic resampling and one
+// resamples after every update. The default is 0.5.
+func WithResampleThreshold(threshold float64) ParticleFilterOption {
+	return func(pf *ParticleFilter) {
+		pf.threshold = threshold
+	}
+}
+
+// WithSeed seeds the random number generator. The default seed is 1, so runs are reproducible
+// unless a different seed is chosen.
+func WithSeed(seed int64) ParticleFilterOption {
+	return func(pf *ParticleFilter) {
+		pf.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// NewParticleFilter creates a new particle filter.
+//
+// Parameters:
+//   - initial: Initial particle states, which are copied and given equal weights. Every
+//     particle must have the same, non-zero dimension.
+//   - motion: Motion model applied to each particle by Predict
+//   - likelihood: Measurement likelihood applied to each particle by Update
+//   - opts: Optional configuration
+//
+// Returns an error if there are no particles, the dimensions differ, a function is nil or
+// an option is out of range.
+func NewParticleFilter(initial [][]float64, motion MotionModel, likelihood LikelihoodFunc, opts ...ParticleFilterOption) (*ParticleFilter, error) {
+	if motion == nil || likelihood == nil {
+		return nil, errors.New("motion and likelihood functions must not be nil")
+	}
+
+	pf := &ParticleFilter{
+		motion:     motion,
+		likelihood: likelihood,
+		method:     SystematicResampling,
+		threshold:  0.5,
+		rng:        rand.New(rand.NewSource(1)),
+	}
+	for _, opt := range opts {
+		opt(pf)
+	}
+
+	if pf.threshold < 0 || pf.threshold > 1 {
+		return nil, errors.New("resample threshold must be between 0 and 1")
+	}
+	if pf.method != SystematicResampling && pf.method != StratifiedResampling {
+		return nil, errors.New("unknown resampling method")
+	}
+	if err := pf.SetParticles(initial); err != nil {
+		return nil, err
+	}
+
+	return pf, nil
+}
+
+// SetParticles replaces the particles with copies of the given states and gives them equal weights.
+// Returns an error if there are no particles or the dimensions differ.
+func (pf *ParticleFilter) SetParticles(states [][]float64) error {
+	if len(states) == 0 {
+		return errors.New("at least one particle is required")
+	}
+	dim := len(states[0])
+	if dim == 0 {
+		return errors.New("particle dimension must be positive")
+	}
+	for _, state := range states {
+		if len(state) != dim {
+			return errors.New("all particles must have the same dimension")
+		}
+	}
+
+	n := len(states)
+	pf.dim = dim
+	pf.particles = newParticleStorage(n, dim)
+	pf.scratch = newParticleStorage(n, dim)
+	pf.weights = make([]float64, n)
+	pf.cumulative = make([]float64, n)
+	for i, state := range states {
+		copy(pf.particles[i], state)
+		pf.weights[i] = 1.0 / float64(n)
+	}
+	return nil
+}
+
+// Predict propagates every particle through the motion model over dt seconds.
+func (pf *ParticleFilter) Predict(dt float64) {
+	for _, particle := range pf.particles {
+		pf.motion(particle, dt, pf.rng)
+	}
+}
+
+// Update weights the particles by the likelihood of the measurement and resamples them if
+// the effective sample size has fallen below the threshold.
+//
+// If the likelihood of any particle is negative or NaN the weights are left unchanged and
+// ErrInvalidLikelihood is returned. If every particle has zero likelihood the weights are reset
+// to be equal and ErrDegenerateWeights is returned, so the filter can continue from its prior.
+func (pf *ParticleFilter) Update(measurement []float64) error {
+	// The likelihoods are validated before any weight changes, using the resampling scratch space
+	likelihoods := pf.cumulative
+	for i, particle := range pf.particles {
+		l := pf.likelihood(particle, measurement)
+		if !(l >= 0) {
+			return ErrInvalidLikelihood
+		}
+		likelihoods[i] = l
+	}
+
+	var total float64
+	for i, l := range likelihoods {
+		pf.weights[i] *= l
+		total += pf.weights[i]
+	}
+
+	n := float64(len(pf.weights))
+	if total <= 0 || math.IsNaN(total) || math.IsInf(total, 0) {
+		for i := range pf.weights {
+			pf.weights[i] = 1.0 / n
+		}
+		return ErrDegenerateWeights
+	}
+	for i := range pf.weights {
+		pf.weights[i] /= total
+	}
+
+	if pf.EffectiveSampleSize() < pf.threshold*n {
+		pf.Resample()
+	}
+	return nil
+}
+
+// Step calls Predict followed by Update.
+func (pf *ParticleFilter) Step(dt float64, measurement []float64) error {
+	pf.Predict(dt)
+	return pf.Update(measurement)
+}
+
+// EffectiveSampleSize returns 1 / sum(w^2), the number of equally weighted particles that
+// would carry the same information. It ranges from 1 to the number of particles.
+func (pf *ParticleFilter) EffectiveSampleSize() float64 {
+	var sum float64
+	for _, w := range pf.weights {
+		sum += w * w
+	}
+	return 1.0 / sum
+}
+
+// Resample draws a new set of equally weighted particles in proportion to the current weights
+// using the configured resampling method.
+func (pf *ParticleFilter) Resample() {
+	n := len(pf.particles)
+
+	var sum float64
+	for i, w := range pf.weights {
+		sum += w
+		pf.cumulative[i] = sum
+	}
+	// Guard against rounding leaving the last cumulative weight just below one
+	pf.cumulative[n-1] = math.Inf(1)
+
+	offset := pf.rng.Float64()
+	j := 0
+	for i := range n {
+		if pf.method == StratifiedResampling && i > 0 {
+			offset = pf.rng.Float64()
+		}
+		u := (float64(i) + offset) / float64(n)
+		for pf.cumulative[j] < u {
+			j++
+		}
+		copy(pf.scratch[i], pf.particles[j])
+	}
+
+	pf.particles, pf.scratch = pf.scratch, pf.particles
+	for i := range pf.weights {
+		pf.weights[i] = 1.0 / float64(n)
+	}
+}
+
+// Mean returns the weighted mean of the particles.
+func (pf *ParticleFilter) Mean() []float64 {
+	mean := make([]float64, pf.dim)
+	for i, particle := range pf.particles {
+		w := pf.weights[i]
+		for d, v := range particle {
+			mean[d] += w * v
+		}
+	}
+	return mean
+}
+
+// Covariance returns the weighted covariance of the particles about their weighted mean.
+func (pf *ParticleFilter) Covariance() [][]float64 {
+	mean := pf.Mean()
+	covariance := make([][]float64, pf.dim)
+	for r := range covariance {
+		covariance[r] = make([]float64, pf.dim)
+	}
+
+	for i, particle := range pf.particles {
+		w := pf.weights[i]
+		for r := range pf.dim {
+			dr := particle[r] - mean[r]
+			for c := r; c < pf.dim; c++ {
+				covariance[r][c] += w * dr * (particle[c] - mean[c])
+			}
+		}
+	}
+	for r := range pf.dim {
+		for c := range r {
+			covariance[r][c] = covariance[c][r]
+		}
+	}
+	return covariance
+}
+
+// MaxWeight returns a copy of the particle with the largest weight, which can be a better
+// point estimate than the mean when the distribution is multimodal.
+func (pf *ParticleFilter) MaxWeight() []float64 {
+	best := 0
+	for i, w := range pf.weights {
+		if w > pf.weights[best] {
+			best = i
+		}
+	}
+	state := make([]float64, pf.dim)
+	copy(state, pf.particles[best])
+	return state
+}
+
+// Particle returns a copy of the state of the i-th particle and its weight.
+// Returns nil and 0 if the index is out of range.
+func (pf *ParticleFilter) Particle(i int) ([]float64, float64) {
+	if i < 0 || i >= len(pf.particles) {
+		return nil, 0.0
+	}
+	state := make([]float64, pf.dim)
+	copy(state, pf.particles[i])
+	return state, pf.weights[i]
+}
+
+// Weights returns a copy of the normalized particle weights.
+func (pf *ParticleFilter) Weights() []float64 {
+	weights := make([]float64, len(pf.weights))
+	copy(weights, pf.weights)
+	return weights
+}
+
+// Len returns the number of particles.
+func (pf *ParticleFilter) Len() int {
+	return len(pf.particles)
+}
+
+// Dim returns the dimension of the state.
+func (pf *ParticleFilter) Dim() int {
+	return pf.dim
+}
+
+// newParticleStorage allocates n particles of the given dimension backed by a single slice.
+func newParticleStorage(n, dim int) [][]float64 {
+	data := make([]float64, n*dim)
+	particles := make([][]float64, n)
+	for i := range particles {
+		particles[i] = data[i*dim : (i+1)*dim : (i+1)*dim]
+	}
+	return particles
+}
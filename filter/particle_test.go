@@ -0,0 +1,202 @@
+package filter
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestParticleFilter tests the ParticleFilter functionality
+func TestParticleFilter(t *testing.T) {
+	// A robot moves along a corridor at 1 m/s and measures the distance to the nearest of
+	// several identical landmarks, so a single measurement is ambiguous.
+	landmarks := []float64{10, 40, 55, 80}
+	nearest := func(x float64) float64 {
+		distance := math.Inf(1)
+		for _, l := range landmarks {
+			distance = min(distance, math.Abs(x-l))
+		}
+		return distance
+	}
+	motion := func(state []float64, dt float64, rng *rand.Rand) {
+		state[0] += state[1]*dt + 0.05*rng.NormFloat64()
+	}
+	likelihood := func(state, measurement []float64) float64 {
+		e := (nearest(state[0]) - measurement[0]) / 0.5
+		return math.Exp(-0.5 * e * e)
+	}
+	uniform := func(n int, seed int64) [][]float64 {
+		rng := rand.New(rand.NewSource(seed))
+		particles := make([][]float64, n)
+		for i := range particles {
+			particles[i] = []float64{100 * rng.Float64(), 1.0}
+		}
+		return particles
+	}
+
+	t.Run("Constructor validation", func(t *testing.T) {
+		valid := uniform(10, 1)
+		if _, err := NewParticleFilter(nil, motion, likelihood); err == nil {
+			t.Error("Expected error for no particles")
+		}
+		if _, err := NewParticleFilter([][]float64{{1, 2}, {1}}, motion, likelihood); err == nil {
+			t.Error("Expected error for mismatched dimensions")
+		}
+		if _, err := NewParticleFilter(valid, nil, likelihood); err == nil {
+			t.Error("Expected error for nil motion model")
+		}
+		if _, err := NewParticleFilter(valid, motion, likelihood, WithResampleThreshold(1.5)); err == nil {
+			t.Error("Expected error for threshold above 1")
+		}
+		if _, err := NewParticleFilter(valid, motion, likelihood, WithResampling(ResamplingMethod(9))); err == nil {
+			t.Error("Expected error for unknown resampling method")
+		}
+
+		pf, err := NewParticleFilter(valid, motion, likelihood)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if pf.Len() != 10 || pf.Dim() != 2 {
+			t.Errorf("Expected 10 particles of dimension 2, got %d of %d", pf.Len(), pf.Dim())
+		}
+		valid[0][0] = -1
+		if state, _ := pf.Particle(0); state[0] == -1 {
+			t.Error("Expected the initial particles to be copied")
+		}
+	})
+
+	for _, method := range []ResamplingMethod{SystematicResampling, StratifiedResampling} {
+		t.Run("Multimodal localization", func(t *testing.T) {
+			pf, err := NewParticleFilter(uniform(2000, 2), motion, likelihood, WithResampling(method), WithSeed(7))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			truth := 3.0
+			rng := rand.New(rand.NewSource(3))
+			for range 60 {
+				truth += 1.0
+				measurement := []float64{nearest(truth) + 0.5*rng.NormFloat64()}
+				if err := pf.Step(1.0, measurement); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mean := pf.Mean()
+			if math.Abs(mean[0]-truth) > 1.0 {
+				t.Errorf("Method %d: expected position near %f, got %f", method, truth, mean[0])
+			}
+			if variance := pf.Covariance()[0][0]; variance > 1.0 {
+				t.Errorf("Method %d: expected a concentrated estimate, got variance %f", method, variance)
+			}
+			if best := pf.MaxWeight(); math.Abs(best[0]-truth) > 1.5 {
+				t.Errorf("Method %d: expected best particle near %f, got %f", method, truth, best[0])
+			}
+		})
+	}
+
+	t.Run("Reproducible with a seed", func(t *testing.T) {
+		run := func() []float64 {
+			pf, _ := NewParticleFilter(uniform(200, 4), motion, likelihood, WithSeed(42))
+			for i := range 10 {
+				_ = pf.Step(1.0, []float64{float64(i % 5)})
+			}
+			return pf.Mean()
+		}
+		a, b := run(), run()
+		if a[0] != b[0] || a[1] != b[1] {
+			t.Errorf("Expected identical runs, got %v and %v", a, b)
+		}
+	})
+
+	t.Run("Effective sample size and resampling", func(t *testing.T) {
+		particles := [][]float64{{0}, {1}, {2}, {3}}
+		weightByState := func(state, _ []float64) float64 {
+			return []float64{1, 1, 1, 5}[int(state[0])]
+		}
+		pf, _ := NewParticleFilter(particles, motion, weightByState, WithResampleThreshold(0))
+		if math.Abs(pf.EffectiveSampleSize()-4) > 1e-9 {
+			t.Errorf("Expected ESS 4 for equal weights, got %f", pf.EffectiveSampleSize())
+		}
+
+		if err := pf.Update(nil); err != nil {
+			t.Fatal(err)
+		}
+		if ess := pf.EffectiveSampleSize(); math.Abs(ess-64.0/28.0) > 1e-12 {
+			t.Errorf("Expected ESS 64/28, got %f", ess)
+		}
+		weights := pf.Weights()
+		if math.Abs(weights[3]-0.625) > 1e-12 {
+			t.Errorf("Expected weight 0.625, got %f", weights[3])
+		}
+
+		// Systematic resampling keeps floor(N*w) copies of each particle
+		pf.Resample()
+		count := 0
+		for i := range pf.Len() {
+			state, w := pf.Particle(i)
+			if w != 0.25 {
+				t.Errorf("Expected equal weights after resampling, got %f", w)
+			}
+			if state[0] == 3 {
+				count++
+			}
+		}
+		if count < 2 {
+			t.Errorf("Expected at least 2 copies of the heaviest particle, got %d", count)
+		}
+	})
+
+	t.Run("Covariance", func(t *testing.T) {
+		particles := [][]float64{{1, 2}, {3, 6}, {5, 10}}
+		pf, _ := NewParticleFilter(particles, motion, likelihood)
+		mean := pf.Mean()
+		if math.Abs(mean[0]-3) > 1e-12 || math.Abs(mean[1]-6) > 1e-12 {
+			t.Errorf("Expected mean [3 6], got %v", mean)
+		}
+		covariance := pf.Covariance()
+		expected := [][]float64{{8.0 / 3, 16.0 / 3}, {16.0 / 3, 32.0 / 3}}
+		for r := range expected {
+			for c := range expected[r] {
+				if math.Abs(covariance[r][c]-expected[r][c]) > 1e-12 {
+					t.Errorf("Covariance[%d][%d]: expected %f, got %f", r, c, expected[r][c], covariance[r][c])
+				}
+			}
+		}
+	})
+
+	t.Run("Degenerate weights", func(t *testing.T) {
+		zero := func(_, _ []float64) float64 { return 0 }
+		pf, _ := NewParticleFilter(uniform(10, 5), motion, zero)
+		if err := pf.Update(nil); !errors.Is(err, ErrDegenerateWeights) {
+			t.Errorf("Expected ErrDegenerateWeights, got %v", err)
+		}
+		if math.Abs(pf.EffectiveSampleSize()-10) > 1e-9 {
+			t.Errorf("Expected weights to be reset to equal, got ESS %f", pf.EffectiveSampleSize())
+		}
+	})
+
+	t.Run("Invalid likelihood", func(t *testing.T) {
+		for _, bad := range []float64{-1, math.NaN()} {
+			calls := 0
+			likelihood := func(state, _ []float64) float64 {
+				calls++
+				if calls == 7 {
+					return bad
+				}
+				return 1 + state[0]
+			}
+			pf, _ := NewParticleFilter(uniform(10, 5), motion, likelihood)
+			before := pf.Weights()
+			if err := pf.Update(nil); !errors.Is(err, ErrInvalidLikelihood) {
+				t.Errorf("Likelihood %f: expected ErrInvalidLikelihood, got %v", bad, err)
+			}
+			for i, w := range pf.Weights() {
+				if w != before[i] {
+					t.Errorf("Likelihood %f: expected weight %d to be unchanged, got %f", bad, i, w)
+				}
+			}
+		}
+	})
+}
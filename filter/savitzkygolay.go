@@ -0,0 +1,213 @@
+package filter
+
+import (
+	"errors"
+	"math"
+
+	"control/linalg"
+)
+
+// SavitzkyGolayFilter implements a causal Savitzky–Golay smoothing and differentiation filter.
+//
+// For every new sample a polynomial of the given degree is fitted by least squares to the
+// most recent N samples and evaluated at the newest sample. Because the fit is linear in the
+// samples, the smoothed value and the first and second derivatives are each a fixed
+// convolution of the window, so the coefficients are computed once at construction.
+// Compared with a first difference, the derivative is far less sensitive to quantization
+// noise such as encoder ticks, at the cost of some lag.
+//
+// The filter assumes evenly spaced samples. It implements both the Filter and the
+// Differentiator interfaces.
+type SavitzkyGolayFilter struct {
+	window int     // Number of samples in the window
+	degree int     // Degree of the fitted polynomial
+	dt     float64 // Nominal time between samples, used by Estimate
+
+	// coefficients[n-1][k] holds the convolution coefficients for the k-th derivative
+	// (k = 0, 1, 2) when the window holds n samples, ordered from oldest to newest.
+	coefficients [][3][]float64
+	samples      *Float64Stack // Samples in arrival order (0 = oldest)
+
+	value            float64 // Smoothed value at the newest sample
+	derivative       float64 // First derivative at the newest sample
+	secondDerivative float64 // Second derivative at the newest sample
+}
+
+// NewSavitzkyGolayFilter creates a new Savitzky–Golay filter.
+//
+// Parameters:
+//   - window: Number of samples the polynomial is fitted to
+//   - degree: Degree of the fitted polynomial (0 <= degree < window). Degree 2 or more is
+//     required for a non-zero second derivative.
+//   - dt: Nominal time between samples in seconds, used to scale the derivatives in Estimate
+//
+// Returns an error if the parameters are out of range or the fit is too ill-conditioned to
+// solve, which can happen for very high degrees.
+func NewSavitzkyGolayFilter(window, degree int, dt float64) (*SavitzkyGolayFilter, error) {
+	if window <= 0 {
+		return nil, errors.New("window size must be positive")
+	}
+	if degree < 0 || degree >= window {
+		return nil, errors.New("degree must be non-negative and less than the window size")
+	}
+	if dt <= 0 {
+		return nil, errors.New("dt must be positive")
+	}
+
+	sgf := &SavitzkyGolayFilter{
+		window:       window,
+		degree:       degree,
+		dt:           dt,
+		coefficients: make([][3][]float64, window),
+		samples:      NewFloat64Stack(window),
+	}
+
+	// Until the window is full, fit the highest degree the available samples support
+	for n := 1; n <= window; n++ {
+		coefficients, err := savitzkyGolayCoefficients(n, min(degree, n-1))
+		if err != nil {
+			return nil, err
+		}
+		sgf.coefficients[n-1] = coefficients
+	}
+
+	return sgf, nil
+}
+
+// Estimate adds the measurement to the window and returns the smoothed value.
+// The derivatives, scaled by the nominal dt, are available from GetDerivative and GetSecondDerivative.
+// This implements the Filter interface.
+func (sgf *SavitzkyGolayFilter) Estimate(measurement float64) float64 {
+	sgf.update(measurement, sgf.dt)
+	return sgf.value
+}
+
+// Differentiate adds the value to the window and returns the first derivative, assuming the
+// samples are dt seconds apart. A non-positive dt adds the sample and returns 0.
+// This implements the Differentiator interface.
+func (sgf *SavitzkyGolayFilter) Differentiate(value, dt float64) float64 {
+	sgf.update(value, dt)
+	return sgf.derivative
+}
+
+// update pushes a sample and recomputes the smoothed value and derivatives.
+func (sgf *SavitzkyGolayFilter) update(measurement, dt float64) {
+	sgf.samples.Push(measurement)
+	coefficients := sgf.coefficients[sgf.samples.Size()-1]
+
+	var value, derivative, secondDerivative float64
+	i := 0
+	for v := range sgf.samples.All() {
+		value += coefficients[0][i] * v
+		derivative += coefficients[1][i] * v
+		secondDerivative += coefficients[2][i] * v
+		i++
+	}
+
+	sgf.value = value
+	if dt > 0 {
+		sgf.derivative = derivative / dt
+		sgf.secondDerivative = secondDerivative / (dt * dt)
+	} else {
+		sgf.derivative = 0
+		sgf.secondDerivative = 0
+	}
+}
+
+// GetValue returns the smoothed value at the newest sample.
+func (sgf *SavitzkyGolayFilter) GetValue() float64 {
+	return sgf.value
+}
+
+// GetDerivative returns the first derivative at the newest sample.
+func (sgf *SavitzkyGolayFilter) GetDerivative() float64 {
+	return sgf.derivative
+}
+
+// GetSecondDerivative returns the second derivative at the newest sample.
+func (sgf *SavitzkyGolayFilter) GetSecondDerivative() float64 {
+	return sgf.secondDerivative
+}
+
+// GetCoefficients returns a copy of the convolution coefficients for the given derivative
+// order (0, 1 or 2) with a full window, ordered from the oldest sample to the newest. The
+// coefficients are in units of samples; divide by dt^order to obtain physical units.
+// Returns nil for any other order.
+func (sgf *SavitzkyGolayFilter) GetCoefficients(order int) []float64 {
+	if order < 0 || order > 2 {
+		return nil
+	}
+	coefficients := make([]float64, sgf.window)
+	copy(coefficients, sgf.coefficients[sgf.window-1][order])
+	return coefficients
+}
+
+// GetGain returns the smoothing coefficient applied to the newest sample once the window is full.
+// This method exists to satisfy the Filter interface.
+func (sgf *SavitzkyGolayFilter) GetGain() float64 {
+	return sgf.coefficients[sgf.window-1][0][sgf.window-1]
+}
+
+// GetSize returns the size of the window.
+func (sgf *SavitzkyGolayFilter) GetSize() int {
+	return sgf.window
+}
+
+// GetDegree returns the degree of the fitted polynomial.
+func (sgf *SavitzkyGolayFilter) GetDegree() int {
+	return sgf.degree
+}
+
+// Reset clears the window and the estimates.
+func (sgf *SavitzkyGolayFilter) Reset() {
+	sgf.samples.Clear()
+	sgf.value = 0.0
+	sgf.derivative = 0.0
+	sgf.secondDerivative = 0.0
+}
+
+// savitzkyGolayCoefficients computes the end-point convolution coefficients for a window of
+// n samples and a polynomial of the given degree. The samples are placed at t = -(n-1)..0,
+// so the k-th polynomial coefficient is the k-th derivative at the newest sample divided by k!.
+// Returns an error if the Gram matrix is singular to working precision.
+func savitzkyGolayCoefficients(n, degree int) ([3][]float64, error) {
+	m := degree + 1
+
+	// Gram matrix G = J^T J, where J[i][j] = u_i^j with the sample times scaled to u = t/scale
+	// in [-1, 0] to keep G well conditioned for long windows. G is positive definite because
+	// degree < n, but for high degrees it can still be singular in floating point.
+	scale := float64(max(n-1, 1))
+	gram := linalg.New(m, m)
+	for r := range gram {
+		for c := range m {
+			for i := range n {
+				u := float64(i-(n-1)) / scale
+				gram[r][c] += math.Pow(u, float64(r+c))
+			}
+		}
+	}
+	inverse, err := gram.Inverse()
+	if err != nil {
+		return [3][]float64{}, err
+	}
+
+	// Row k of G^-1 J^T gives the k-th polynomial coefficient in u as a convolution of the
+	// samples, and dividing by scale^k converts it back to t
+	var result [3][]float64
+	for k := range result {
+		result[k] = make([]float64, n)
+		if k >= m {
+			continue
+		}
+		factorial := float64(max(k, 1)) / math.Pow(scale, float64(k))
+		for i := range n {
+			u := float64(i-(n-1)) / scale
+			var sum float64
+			for j := range m {
+				sum += inverse[k][j] * math.Pow(u, float64(j))
+			}
+			result[k][i] = factorial * sum
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package filter
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSavitzkyGolayFilter tests the SavitzkyGolayFilter functionality
+func TestSavitzkyGolayFilter(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			window, degree int
+			dt             float64
+		}{
+			{"Zero window", 0, 0, 0.01},
+			{"Negative degree", 5, -1, 0.01},
+			{"Degree too large", 5, 5, 0.01},
+			{"Zero dt", 5, 2, 0.0},
+			{"Ill-conditioned fit", 30, 20, 0.01},
+		}
+		for _, tt := range tests {
+			if _, err := NewSavitzkyGolayFilter(tt.window, tt.degree, tt.dt); err == nil {
+				t.Errorf("%s: expected error", tt.name)
+			}
+		}
+
+		sgf, err := NewSavitzkyGolayFilter(7, 2, 0.01)
+		if err != nil {
+			t.Fatalf("Expected no error for valid parameters, got %v", err)
+		}
+		var _ Filter = sgf
+		var _ Differentiator = sgf
+	})
+
+	t.Run("Coefficient properties", func(t *testing.T) {
+		sgf, _ := NewSavitzkyGolayFilter(9, 3, 0.01)
+
+		sums := []float64{1, 0, 0}
+		for order, expected := range sums {
+			var sum float64
+			for _, c := range sgf.GetCoefficients(order) {
+				sum += c
+			}
+			if math.Abs(sum-expected) > 1e-9 {
+				t.Errorf("Order %d: expected coefficients to sum to %f, got %f", order, expected, sum)
+			}
+		}
+
+		// A straight line has a slope of 1 per sample
+		var slope float64
+		for i, c := range sgf.GetCoefficients(1) {
+			slope += c * float64(i)
+		}
+		if math.Abs(slope-1) > 1e-9 {
+			t.Errorf("Expected derivative coefficients to recover unit slope, got %f", slope)
+		}
+
+		if sgf.GetCoefficients(3) != nil {
+			t.Error("Expected nil coefficients for unsupported order")
+		}
+	})
+
+	t.Run("Exact on polynomials", func(t *testing.T) {
+		dt := 0.02
+		sgf, _ := NewSavitzkyGolayFilter(11, 2, dt)
+
+		f := func(t float64) float64 { return 1.5 - 2*t + 4*t*t }
+		var time float64
+		for i := range 30 {
+			time = float64(i) * dt
+			sgf.Estimate(f(time))
+		}
+
+		if math.Abs(sgf.GetValue()-f(time)) > 1e-9 {
+			t.Errorf("Expected value %f, got %f", f(time), sgf.GetValue())
+		}
+		if math.Abs(sgf.GetDerivative()-(-2+8*time)) > 1e-6 {
+			t.Errorf("Expected derivative %f, got %f", -2+8*time, sgf.GetDerivative())
+		}
+		if math.Abs(sgf.GetSecondDerivative()-8) > 1e-4 {
+			t.Errorf("Expected second derivative 8, got %f", sgf.GetSecondDerivative())
+		}
+	})
+
+	t.Run("Partial window", func(t *testing.T) {
+		sgf, _ := NewSavitzkyGolayFilter(5, 2, 1.0)
+
+		if d := sgf.Differentiate(3.0, 1.0); d != 0.0 {
+			t.Errorf("Expected zero derivative from a single sample, got %f", d)
+		}
+		if d := sgf.Differentiate(5.0, 1.0); math.Abs(d-2.0) > 1e-9 {
+			t.Errorf("Expected derivative 2.0 from two samples, got %f", d)
+		}
+	})
+
+	t.Run("Quantized encoder derivative", func(t *testing.T) {
+		dt := 0.005
+		velocity := 37.3 // ticks per second
+		sgf, _ := NewSavitzkyGolayFilter(25, 2, dt)
+
+		var previous, firstDifferenceError, sgError float64
+		for i := range 2000 {
+			ticks := math.Floor(velocity * float64(i) * dt)
+			d := sgf.Differentiate(ticks, dt)
+			if i > 100 {
+				firstDifferenceError += math.Pow((ticks-previous)/dt-velocity, 2)
+				sgError += math.Pow(d-velocity, 2)
+			}
+			previous = ticks
+		}
+
+		if sgError >= firstDifferenceError/10 {
+			t.Errorf("Expected Savitzky–Golay error %f to be far below first difference error %f",
+				sgError, firstDifferenceError)
+		}
+	})
+
+	t.Run("Reset functionality", func(t *testing.T) {
+		sgf, _ := NewSavitzkyGolayFilter(5, 1, 0.1)
+		sgf.Estimate(1.0)
+		sgf.Estimate(5.0)
+
+		sgf.Reset()
+		if sgf.GetDerivative() != 0.0 || sgf.GetValue() != 0.0 {
+			t.Error("Expected estimates to be cleared after reset")
+		}
+		if estimate := sgf.Estimate(2.0); estimate != 2.0 {
+			t.Errorf("Expected first estimate after reset 2.0, got %f", estimate)
+		}
+	})
+}
+
+// BenchmarkSavitzkyGolayFilter benchmarks the Savitzky–Golay filter performance
+func BenchmarkSavitzkyGolayFilter(b *testing.B) {
+	sgf, err := NewSavitzkyGolayFilter(21, 2, 0.01)
+	if err != nil {
+		b.Fatalf("Failed to create Savitzky–Golay filter: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sgf.Estimate(float64(i % 100))
+	}
+}
@@ -1,54 +1,196 @@
 package filter
 
-import "slices"
+import (
+	"cmp"
+	"iter"
+	"math"
+)
 
 // SizedStack represents a fixed-size stack that maintains the most recent N elements.
-type SizedStack[T any] []T
+//
+// The elements are stored in a ring buffer, so Push is O(1) even when the stack is full
+// and the oldest element has to be discarded. Because the oldest element is not necessarily
+// first in the buffer, the stack is not a slice; use Get, All or ToArray to read it.
+type SizedStack[T any] struct {
+	data []T // Ring buffer of elements
+	head int // Index of the oldest element in data
+	size int // Number of elements in the stack
+}
 
 // NewSizedStack creates a new generic sized stack with the given capacity.
 func NewSizedStack[T any](capacity int) *SizedStack[T] {
-	s := make(SizedStack[T], 0, capacity)
-	return &s
+	return &SizedStack[T]{
+		data: make([]T, max(capacity, 0)),
+	}
 }
 
 // Push adds an element to the stack, removing the oldest if at capacity.
 func (s *SizedStack[T]) Push(value T) {
-	if len(*s) < cap(*s) {
-		// Still have capacity, append the element
-		*s = append(*s, value)
+	n := len(s.data)
+	if n == 0 {
+		return
+	}
+
+	if s.size < n {
+		// Still have capacity, write after the newest element
+		s.data[(s.head+s.size)%n] = value
+		s.size++
 	} else {
-		// At capacity, shift elements left and add new element at the end
-		copy(*s, (*s)[1:])
-		(*s)[len(*s)-1] = value
+		// At capacity, overwrite the oldest element and advance the head
+		s.data[s.head] = value
+		s.head = (s.head + 1) % n
 	}
 }
 
 // Peek returns the most recently added element without removing it.
 // Returns zero value of T if stack is empty.
 func (s *SizedStack[T]) Peek() T {
-	var zero T
-	if len(*s) == 0 {
-		return zero
-	}
-	return (*s)[len(*s)-1]
+	return s.Newest()
+}
+
+// Newest returns the most recently added element.
+// Returns zero value of T if stack is empty.
+func (s *SizedStack[T]) Newest() T {
+	return s.Get(s.size - 1)
+}
+
+// Oldest returns the least recently added element.
+// Returns zero value of T if stack is empty.
+func (s *SizedStack[T]) Oldest() T {
+	return s.Get(0)
 }
 
 // Get returns the element at the given index (0 = oldest, size-1 = newest).
 // Returns zero value of T if index is out of bounds.
 func (s *SizedStack[T]) Get(index int) T {
 	var zero T
-	if index < 0 || index >= len(*s) {
+	if index < 0 || index >= s.size {
 		return zero
 	}
-	return (*s)[index]
+	return s.data[(s.head+index)%len(s.data)]
 }
 
 // Size returns the current number of elements in the stack.
 func (s *SizedStack[T]) Size() int {
-	return len(*s)
+	return s.size
+}
+
+// Capacity returns the maximum number of elements the stack holds.
+func (s *SizedStack[T]) Capacity() int {
+	return len(s.data)
+}
+
+// IsFull returns whether the stack holds as many elements as its capacity.
+func (s *SizedStack[T]) IsFull() bool {
+	return s.size == len(s.data)
+}
+
+// Clear removes all elements from the stack without releasing its storage.
+func (s *SizedStack[T]) Clear() {
+	clear(s.data)
+	s.head = 0
+	s.size = 0
+}
+
+// All returns an iterator over the elements from oldest to newest. It does not allocate.
+func (s *SizedStack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		// Walk the two contiguous segments of the ring buffer to avoid a modulo per element
+		first := min(s.size, len(s.data)-s.head)
+		for _, v := range s.data[s.head : s.head+first] {
+			if !yield(v) {
+				return
+			}
+		}
+		for _, v := range s.data[:s.size-first] {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the elements from newest to oldest. It does not allocate.
+func (s *SizedStack[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := s.size - 1; i >= 0; i-- {
+			if !yield(s.data[(s.head+i)%len(s.data)]) {
+				return
+			}
+		}
+	}
 }
 
-// ToArray returns a copy of the stack data as a slice.
+// ToArray returns a copy of the stack data as a slice, ordered from oldest to newest.
 func (s *SizedStack[T]) ToArray() []T {
-	return slices.Clone(*s)
+	result := make([]T, 0, s.size)
+	for v := range s.All() {
+		result = append(result, v)
+	}
+	return result
+}
+
+// number is the set of element types supported by the stack statistics helpers.
+type number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// StackMean returns the arithmetic mean of the integer or floating-point elements in the stack.
+// Returns 0 if the stack is empty.
+func StackMean[T number](s *SizedStack[T]) float64 {
+	if s.size == 0 {
+		return 0.0
+	}
+
+	var sum float64
+	for v := range s.All() {
+		sum += float64(v)
+	}
+	return sum / float64(s.size)
+}
+
+// StackVariance returns the population variance of the integer or floating-point elements in
+// the stack. Returns 0 if the stack has fewer than two elements.
+func StackVariance[T number](s *SizedStack[T]) float64 {
+	if s.size < 2 {
+		return 0.0
+	}
+
+	// Welford's algorithm avoids the cancellation of the sum-of-squares formula
+	var mean, m2 float64
+	n := 0
+	for v := range s.All() {
+		n++
+		delta := float64(v) - mean
+		mean += delta / float64(n)
+		m2 += delta * (float64(v) - mean)
+	}
+	return m2 / float64(n)
+}
+
+// StackStdDev returns the population standard deviation of the elements in the stack.
+func StackStdDev[T number](s *SizedStack[T]) float64 {
+	return math.Sqrt(StackVariance(s))
+}
+
+// StackMin returns the smallest element in the stack.
+// Returns zero value of T if stack is empty.
+func StackMin[T cmp.Ordered](s *SizedStack[T]) T {
+	result := s.Oldest()
+	for v := range s.All() {
+		result = min(result, v)
+	}
+	return result
+}
+
+// StackMax returns the largest element in the stack.
+// Returns zero value of T if stack is empty.
+func StackMax[T cmp.Ordered](s *SizedStack[T]) T {
+	result := s.Oldest()
+	for v := range s.All() {
+		result = max(result, v)
+	}
+	return result
 }
@@ -0,0 +1,201 @@
+package filter
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// TestSizedStackRingBuffer tests the ring-buffer behaviour of SizedStack
+func TestSizedStackRingBuffer(t *testing.T) {
+	t.Run("Wrap around", func(t *testing.T) {
+		stack := NewSizedStack[int](3)
+		for i := 1; i <= 7; i++ {
+			stack.Push(i)
+		}
+
+		if !stack.IsFull() || stack.Capacity() != 3 {
+			t.Errorf("Expected full stack of capacity 3, got size %d capacity %d", stack.Size(), stack.Capacity())
+		}
+		if stack.Oldest() != 5 || stack.Newest() != 7 || stack.Peek() != 7 {
+			t.Errorf("Expected oldest 5 and newest 7, got %d and %d", stack.Oldest(), stack.Newest())
+		}
+
+		expected := []int{5, 6, 7}
+		for i, v := range expected {
+			if stack.Get(i) != v {
+				t.Errorf("Expected Get(%d) = %d, got %d", i, v, stack.Get(i))
+			}
+		}
+		if got := slices.Collect(stack.All()); !slices.Equal(got, expected) {
+			t.Errorf("Expected All() = %v, got %v", expected, got)
+		}
+		if got := slices.Collect(stack.Backward()); !slices.Equal(got, []int{7, 6, 5}) {
+			t.Errorf("Expected Backward() = [7 6 5], got %v", got)
+		}
+		if got := stack.ToArray(); !slices.Equal(got, expected) {
+			t.Errorf("Expected ToArray() = %v, got %v", expected, got)
+		}
+		if stack.Get(3) != 0 || stack.Get(-1) != 0 {
+			t.Error("Expected out of bounds Get to return zero value")
+		}
+	})
+
+	t.Run("Early break", func(t *testing.T) {
+		stack := NewFloat64Stack(4)
+		for i := range 6 {
+			stack.Push(float64(i))
+		}
+
+		var visited []float64
+		for v := range stack.All() {
+			visited = append(visited, v)
+			if len(visited) == 2 {
+				break
+			}
+		}
+		if !slices.Equal(visited, []float64{2, 3}) {
+			t.Errorf("Expected [2 3], got %v", visited)
+		}
+	})
+
+	t.Run("Clear and zero capacity", func(t *testing.T) {
+		stack := NewFloat64Stack(2)
+		stack.Push(1.0)
+		stack.Push(2.0)
+		stack.Clear()
+		if stack.Size() != 0 || stack.Peek() != 0.0 {
+			t.Error("Expected empty stack after clear")
+		}
+		stack.Push(3.0)
+		if stack.Oldest() != 3.0 {
+			t.Errorf("Expected 3.0 after clear and push, got %f", stack.Oldest())
+		}
+
+		empty := NewFloat64Stack(0)
+		empty.Push(1.0)
+		if empty.Size() != 0 {
+			t.Errorf("Expected zero-capacity stack to stay empty, got size %d", empty.Size())
+		}
+	})
+
+	t.Run("Iteration does not allocate", func(t *testing.T) {
+		stack := NewFloat64Stack(16)
+		for i := range 40 {
+			stack.Push(float64(i))
+		}
+
+		var sum float64
+		allocs := testing.AllocsPerRun(100, func() {
+			stack.Push(1.0)
+			for v := range stack.All() {
+				sum += v
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("Expected zero allocations, got %f", allocs)
+		}
+	})
+}
+
+// TestSizedStackStatistics tests the stack statistics helpers
+func TestSizedStackStatistics(t *testing.T) {
+	stack := NewFloat64Stack(4)
+	if StackMean(stack) != 0.0 || StackVariance(stack) != 0.0 || StackMin(stack) != 0.0 || StackMax(stack) != 0.0 {
+		t.Error("Expected zero statistics for empty stack")
+	}
+
+	for _, v := range []float64{100.0, 2.0, 4.0, 4.0, 6.0} {
+		stack.Push(v)
+	}
+
+	values := stack.ToArray()
+	if StackMean(stack) != 4.0 {
+		t.Errorf("Expected mean 4.0, got %f", StackMean(stack))
+	}
+	if math.Abs(StackVariance(stack)-calculateVariance(values)) > 1e-12 {
+		t.Errorf("Expected variance %f, got %f", calculateVariance(values), StackVariance(stack))
+	}
+	if math.Abs(StackStdDev(stack)-math.Sqrt(2.0)) > 1e-12 {
+		t.Errorf("Expected standard deviation %f, got %f", math.Sqrt(2.0), StackStdDev(stack))
+	}
+	if StackMin(stack) != 2.0 || StackMax(stack) != 6.0 {
+		t.Errorf("Expected min 2.0 and max 6.0, got %f and %f", StackMin(stack), StackMax(stack))
+	}
+
+	ints := NewSizedStack[int](3)
+	for _, v := range []int{-3, 9, 1} {
+		ints.Push(v)
+	}
+	if StackMean(ints) != 7.0/3.0 || StackMin(ints) != -3 || StackMax(ints) != 9 {
+		t.Errorf("Unexpected integer statistics: mean %f, min %d, max %d", StackMean(ints), StackMin(ints), StackMax(ints))
+	}
+}
+
+// shiftingStack is the previous slice-backed SizedStack implementation, kept as a baseline
+// for the benchmarks below.
+type shiftingStack []float64
+
+func (s *shiftingStack) Push(value float64) {
+	if len(*s) < cap(*s) {
+		*s = append(*s, value)
+	} else {
+		copy(*s, (*s)[1:])
+		(*s)[len(*s)-1] = value
+	}
+}
+
+func (s *shiftingStack) ToArray() []float64 {
+	return slices.Clone(*s)
+}
+
+// BenchmarkSizedStackPush benchmarks pushing into a full ring-buffer stack
+func BenchmarkSizedStackPush(b *testing.B) {
+	for _, size := range []int{8, 128, 1024} {
+		b.Run("RingBuffer/"+strconv.Itoa(size), func(b *testing.B) {
+			stack := NewFloat64Stack(size)
+			for i := 0; i < b.N; i++ {
+				stack.Push(float64(i))
+			}
+		})
+		b.Run("Shifting/"+strconv.Itoa(size), func(b *testing.B) {
+			s := make(shiftingStack, 0, size)
+			stack := &s
+			for i := 0; i < b.N; i++ {
+				stack.Push(float64(i))
+			}
+		})
+	}
+}
+
+// BenchmarkSizedStackIterate benchmarks summing the elements of a full stack
+func BenchmarkSizedStackIterate(b *testing.B) {
+	size := 128
+	stack := NewFloat64Stack(size)
+	s := make(shiftingStack, 0, size)
+	old := &s
+	for i := range size {
+		stack.Push(float64(i))
+		old.Push(float64(i))
+	}
+
+	b.Run("All", func(b *testing.B) {
+		b.ReportAllocs()
+		var sum float64
+		for i := 0; i < b.N; i++ {
+			for v := range stack.All() {
+				sum += v
+			}
+		}
+	})
+	b.Run("ToArray", func(b *testing.B) {
+		b.ReportAllocs()
+		var sum float64
+		for i := 0; i < b.N; i++ {
+			for _, v := range old.ToArray() {
+				sum += v
+			}
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package filter
+
+import (
+	"errors"
+	"slices"
+)
+
+// RTSSmoother implements a Rauch–Tung–Striebel fixed-interval smoother for recorded data.
+//
+// The signal is modeled as a random walk observed with noise, the same model as a scalar
+// Kalman filter. A forward Kalman pass computes the causal estimates, then a backward pass
+// corrects every estimate using the measurements that followed it. The result has no lag
+// and a lower variance than any causal filter, which makes it suitable for analysing logs
+// but not for use inside a control loop.
+type RTSSmoother struct {
+	q float64 // Process noise variance per sample
+	r float64 // Measurement noise variance
+
+	// Scratch buffers reused between calls
+	filtered  []float64 // Forward (causal) estimates
+	filteredP []float64 // Forward error covariances
+	variances []float64 // Smoothed error covariances
+}
+
+// NewRTSSmoother creates a new Rauch–Tung–Striebel smoother.
+//
+// Parameters:
+//   - processNoise: Variance of the change in the signal between samples
+//   - measurementNoise: Variance of the measurement noise
+//
+// Returns an error if either variance is negative or the measurement noise is zero.
+func NewRTSSmoother(processNoise, measurementNoise float64) (*RTSSmoother, error) {
+	if processNoise < 0 {
+		return nil, errors.New("process noise must be non-negative")
+	}
+	if measurementNoise <= 0 {
+		return nil, errors.New("measurement noise must be positive")
+	}
+
+	return &RTSSmoother{
+		q: processNoise,
+		r: measurementNoise,
+	}, nil
+}
+
+// NewRTSSmootherFromKalman creates a smoother with the same covariances as a KalmanFilter, so
+// that recorded runs can be compared with the estimates the filter produced online.
+func NewRTSSmootherFromKalman(kf *KalmanFilter) (*RTSSmoother, error) {
+	if kf == nil {
+		return nil, errors.New("kalman filter must not be nil")
+	}
+	return NewRTSSmoother(kf.q, kf.r)
+}
+
+// Smooth returns the smoothed estimates of the measurements.
+func (s *RTSSmoother) Smooth(measurements []float64) []float64 {
+	out := make([]float64, len(measurements))
+	s.SmoothInto(measurements, out)
+	return out
+}
+
+// SmoothInto writes the smoothed estimates of the measurements into out, which must be at least
+// as long as measurements. Internal buffers are reused, so repeated calls with sequences of the
+// same length do not allocate.
+func (s *RTSSmoother) SmoothInto(measurements, out []float64) {
+	n := len(measurements)
+	if n == 0 {
+		return
+	}
+	s.filtered = slices.Grow(s.filtered[:0], n)[:n]
+	s.filteredP = slices.Grow(s.filteredP[:0], n)[:n]
+	s.variances = slices.Grow(s.variances[:0], n)[:n]
+
+	// Forward pass, initialized with the first measurement
+	x, p := measurements[0], s.r
+	s.filtered[0], s.filteredP[0] = x, p
+	for k := 1; k < n; k++ {
+		prior := p + s.q
+		gain := prior / (prior + s.r)
+		x += gain * (measurements[k] - x)
+		p = (1 - gain) * prior
+		s.filtered[k], s.filteredP[k] = x, p
+	}
+
+	// Backward pass
+	out[n-1] = s.filtered[n-1]
+	s.variances[n-1] = s.filteredP[n-1]
+	for k := n - 2; k >= 0; k-- {
+		prior := s.filteredP[k] + s.q
+		gain := s.filteredP[k] / prior
+		out[k] = s.filtered[k] + gain*(out[k+1]-s.filtered[k])
+		s.variances[k] = s.filteredP[k] + gain*gain*(s.variances[k+1]-prior)
+	}
+}
+
+// Variances returns a copy of the error variances of the most recently smoothed sequence.
+func (s *RTSSmoother) Variances() []float64 {
+	return slices.Clone(s.variances)
+}
+
+// GetNoise returns the process and measurement noise variances.
+func (s *RTSSmoother) GetNoise() (processNoise, measurementNoise float64) {
+	return s.q, s.r
+}
+
+// FiltFilt applies the filter forwards and then backwards over the input and returns the result.
+//
+// Running the filter in both directions cancels its phase lag, so features in the output line
+// up with the input, and squares its magnitude response. The filter is reset before each pass
+// and after the second, so it must not be in use elsewhere. The result is only zero-phase for
+// linear, time-invariant filters such as LowPassFilter.
+func FiltFilt(f Filter, in []float64) []float64 {
+	out := make([]float64, len(in))
+	FiltFiltInto(f, in, out)
+	return out
+}
+
+// FiltFiltInto is like FiltFilt but writes the result into out, which must be at least as long
+// as in. in and out may be the same slice.
+func FiltFiltInto(f Filter, in, out []float64) {
+	out = out[:len(in)]
+
+	f.Reset()
+	EstimateAll(f, in, out)
+
+	// Run the backward pass over the reversed forward output
+	f.Reset()
+	slices.Reverse(out)
+	EstimateAll(f, out, out)
+	slices.Reverse(out)
+	f.Reset()
+}
@@ -0,0 +1,155 @@
+package filter
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestRTSSmoother tests the RTSSmoother functionality
+func TestRTSSmoother(t *testing.T) {
+	t.Run("Constructor validation", func(t *testing.T) {
+		if _, err := NewRTSSmoother(-1, 1); err == nil {
+			t.Error("Expected error for negative process noise")
+		}
+		if _, err := NewRTSSmoother(1, 0); err == nil {
+			t.Error("Expected error for zero measurement noise")
+		}
+		if _, err := NewRTSSmootherFromKalman(nil); err == nil {
+			t.Error("Expected error for nil Kalman filter")
+		}
+
+		kf, _ := NewKalmanFilter(0.2, 0.5, 3)
+		s, err := NewRTSSmootherFromKalman(kf)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if q, r := s.GetNoise(); q != 0.2 || r != 0.5 {
+			t.Errorf("Expected noise 0.2 and 0.5, got %f and %f", q, r)
+		}
+	})
+
+	t.Run("Constant signal gives the sample mean", func(t *testing.T) {
+		s, _ := NewRTSSmoother(0, 1)
+		measurements := []float64{1, 4, 2, 5, 3}
+		for i, v := range s.Smooth(measurements) {
+			if math.Abs(v-3) > 1e-12 {
+				t.Errorf("Sample %d: expected 3, got %f", i, v)
+			}
+		}
+	})
+
+	t.Run("Better than causal filtering", func(t *testing.T) {
+		const q, r = 0.01, 1.0
+		rng := rand.New(rand.NewSource(1))
+		truth := make([]float64, 1000)
+		measurements := make([]float64, len(truth))
+		x := 0.0
+		for i := range truth {
+			x += math.Sqrt(q) * rng.NormFloat64()
+			truth[i] = x
+			measurements[i] = x + math.Sqrt(r)*rng.NormFloat64()
+		}
+
+		s, _ := NewRTSSmoother(q, r)
+		smoothed := s.Smooth(measurements)
+
+		// Causal estimates from the same model
+		causal := make([]float64, len(truth))
+		est, p := measurements[0], r
+		causal[0] = est
+		for k := 1; k < len(measurements); k++ {
+			prior := p + q
+			gain := prior / (prior + r)
+			est += gain * (measurements[k] - est)
+			p = (1 - gain) * prior
+			causal[k] = est
+		}
+
+		var smoothErr, causalErr float64
+		for i := range truth {
+			smoothErr += (smoothed[i] - truth[i]) * (smoothed[i] - truth[i])
+			causalErr += (causal[i] - truth[i]) * (causal[i] - truth[i])
+		}
+		if smoothErr >= 0.8*causalErr {
+			t.Errorf("Expected smoothed error %f well below causal error %f", smoothErr, causalErr)
+		}
+
+		variances := s.Variances()
+		if len(variances) != len(truth) {
+			t.Fatalf("Expected %d variances, got %d", len(truth), len(variances))
+		}
+		// Interior samples benefit from both directions
+		if variances[500] >= p {
+			t.Errorf("Expected smoothed variance %f below filtered variance %f", variances[500], p)
+		}
+		if math.Abs(variances[len(variances)-1]-p) > 1e-12 {
+			t.Errorf("Expected final variance to equal the filtered variance %f, got %f", p, variances[len(variances)-1])
+		}
+	})
+
+	t.Run("Empty input", func(t *testing.T) {
+		s, _ := NewRTSSmoother(1, 1)
+		if out := s.Smooth(nil); len(out) != 0 {
+			t.Errorf("Expected empty output, got %v", out)
+		}
+	})
+}
+
+// TestFiltFilt tests the FiltFilt helper
+func TestFiltFilt(t *testing.T) {
+	t.Run("Zero phase on a ramp", func(t *testing.T) {
+		lpf, _ := NewLowPassFilter(0.8)
+		ramp := make([]float64, 200)
+		for i := range ramp {
+			ramp[i] = 0.5 * float64(i)
+		}
+
+		forward := make([]float64, len(ramp))
+		for i, v := range ramp {
+			forward[i] = lpf.Estimate(v)
+		}
+		if lag := ramp[100] - forward[100]; lag < 1 {
+			t.Fatalf("Expected the forward pass to lag the ramp, got %f", lag)
+		}
+
+		out := FiltFilt(lpf, ramp)
+		for i := 60; i < 140; i++ {
+			if math.Abs(out[i]-ramp[i]) > 1e-3 {
+				t.Errorf("Sample %d: expected %f, got %f", i, ramp[i], out[i])
+			}
+		}
+		if lpf.IsInitialized() {
+			t.Error("Expected the filter to be reset afterwards")
+		}
+	})
+
+	t.Run("Peak alignment", func(t *testing.T) {
+		lpf, _ := NewLowPassFilter(0.9)
+		in := make([]float64, 400)
+		for i := range in {
+			d := float64(i-200) / 20
+			in[i] = math.Exp(-d * d)
+		}
+
+		out := make([]float64, len(in))
+		FiltFiltInto(lpf, in, out)
+		peak := 0
+		for i := range out {
+			if out[i] > out[peak] {
+				peak = i
+			}
+		}
+		if peak < 199 || peak > 201 {
+			t.Errorf("Expected the peak to stay at 200, got %d", peak)
+		}
+
+		// In place
+		FiltFiltInto(lpf, in, in)
+		for i := range in {
+			if in[i] != out[i] {
+				t.Fatalf("Sample %d: expected in-place result %f, got %f", i, out[i], in[i])
+			}
+		}
+	})
+}
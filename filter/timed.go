@@ -0,0 +1,133 @@
+package filter
+
+// TimedFilter extends Filter with timestamped measurements, so that dropped or irregularly
+// spaced samples can be accounted for.
+type TimedFilter interface {
+	Filter
+
+	// EstimateAt processes a measurement taken at time t, in seconds, and returns the state
+	// estimate. Measurements with a timestamp at or before the previous one are ignored and
+	// the previous estimate is returned.
+	EstimateAt(measurement, t float64) float64
+}
+
+// VarianceReporter is implemented by filters that can report the variance of their estimate.
+type VarianceReporter interface {
+	// Variance returns the variance of the current estimate.
+	Variance() float64
+}
+
+// Timed returns the filter as a TimedFilter. A filter that already implements TimedFilter is
+// returned unchanged. Any other filter is wrapped so that EstimateAt discards out-of-order and
+// repeated samples and otherwise calls Estimate, ignoring the spacing between samples.
+func Timed(f Filter) TimedFilter {
+	if tf, ok := f.(TimedFilter); ok {
+		return tf
+	}
+	return &timedAdapter{Filter: f}
+}
+
+// VarianceOf returns the variance of the filter's estimate and true, or 0 and false if the
+// filter does not report a variance. Filters wrapped by Timed are unwrapped.
+func VarianceOf(f Filter) (float64, bool) {
+	if ta, ok := f.(*timedAdapter); ok {
+		f = ta.Filter
+	}
+	if vr, ok := f.(VarianceReporter); ok {
+		return vr.Variance(), true
+	}
+	return 0.0, false
+}
+
+// timedAdapter adds EstimateAt to a Filter that has no notion of time.
+type timedAdapter struct {
+	Filter
+	clock    sampleClock
+	estimate float64 // Most recent estimate
+}
+
+// EstimateAt passes the measurement to Estimate unless its timestamp is not after the previous one.
+func (ta *timedAdapter) EstimateAt(measurement, t float64) float64 {
+	if _, ok := ta.clock.advance(t); !ok {
+		return ta.estimate
+	}
+	ta.estimate = ta.Estimate(measurement)
+	return ta.estimate
+}
+
+// Reset resets the wrapped filter and forgets the previous timestamp.
+func (ta *timedAdapter) Reset() {
+	ta.Filter.Reset()
+	ta.clock.reset()
+	ta.estimate = 0.0
+}
+
+// clockHistory is the number of recent intervals the sample clock takes the median of.
+const clockHistory = 7
+
+// sampleClock converts measurement timestamps into a number of nominal sample periods.
+//
+// Without a period set by the caller, the nominal period is the lower median of the most recent
+// intervals. A single late sample, such as a slow first sample at startup, therefore does not
+// skew the period once a second interval has arrived, and the lower median favours the regular
+// interval because dropped samples only ever lengthen an interval.
+type sampleClock struct {
+	period    float64               // Nominal sample period set by the caller, or 0 to infer it
+	intervals [clockHistory]float64 // Recent intervals in a ring buffer
+	count     int                   // Number of intervals recorded, up to clockHistory
+	next      int                   // Index in intervals of the next interval to record
+	last      float64               // Timestamp of the previous sample
+	started   bool                  // Whether a sample has been recorded
+}
+
+// advance records the timestamp and returns the number of nominal sample periods since the
+// previous sample. The first sample counts as one period.
+// Returns false, without recording the timestamp, if t is not after the previous timestamp.
+func (sc *sampleClock) advance(t float64) (float64, bool) {
+	if !sc.started {
+		sc.last = t
+		sc.started = true
+		return 1.0, true
+	}
+	if t <= sc.last {
+		return 0.0, false
+	}
+
+	elapsed := t - sc.last
+	sc.last = t
+	sc.intervals[sc.next] = elapsed
+	sc.next = (sc.next + 1) % clockHistory
+	sc.count = min(sc.count+1, clockHistory)
+	return elapsed / sc.nominal(), true
+}
+
+// nominal returns the period set by the caller, or else the lower median of the recent
+// intervals, or 0 if neither is known yet.
+func (sc *sampleClock) nominal() float64 {
+	if sc.period > 0 {
+		return sc.period
+	}
+	if sc.count == 0 {
+		return 0.0
+	}
+
+	// Insertion sort of a copy keeps this allocation-free
+	var sorted [clockHistory]float64
+	for i, v := range sc.intervals[:sc.count] {
+		j := i
+		for ; j > 0 && sorted[j-1] > v; j-- {
+			sorted[j] = sorted[j-1]
+		}
+		sorted[j] = v
+	}
+	return sorted[(sc.count-1)/2]
+}
+
+// reset forgets the previous timestamp and the recorded intervals while keeping the period set
+// by the caller.
+func (sc *sampleClock) reset() {
+	sc.count = 0
+	sc.next = 0
+	sc.last = 0.0
+	sc.started = false
+}
@@ -0,0 +1,217 @@
+package filter
+
+import (
+	"math"
+	"testing"
+)
+
+// TestTimedFilters tests the TimedFilter implementations and adapters
+func TestTimedFilters(t *testing.T) {
+	t.Run("Adapter", func(t *testing.T) {
+		median, _ := NewMedianFilter(3)
+		tf := Timed(median)
+
+		tf.EstimateAt(1.0, 0.0)
+		tf.EstimateAt(5.0, 0.1)
+		if got := tf.EstimateAt(3.0, 0.2); got != 3.0 {
+			t.Errorf("Expected median 3, got %f", got)
+		}
+
+		// Out-of-order and repeated samples are discarded
+		if got := tf.EstimateAt(100.0, 0.2); got != 3.0 {
+			t.Errorf("Expected repeated sample to be ignored, got %f", got)
+		}
+		if got := tf.EstimateAt(100.0, 0.1); got != 3.0 {
+			t.Errorf("Expected out-of-order sample to be ignored, got %f", got)
+		}
+
+		tf.Reset()
+		if got := tf.EstimateAt(7.0, 0.0); got != 7.0 {
+			t.Errorf("Expected reset to accept earlier timestamps, got %f", got)
+		}
+	})
+
+	t.Run("Timed returns native implementations unchanged", func(t *testing.T) {
+		lpf, _ := NewLowPassFilter(0.5)
+		if Timed(lpf) != TimedFilter(lpf) {
+			t.Error("Expected the low-pass filter to be returned unchanged")
+		}
+		kf, _ := NewKalmanFilter(0.1, 1.0, 5)
+		if Timed(kf) != TimedFilter(kf) {
+			t.Error("Expected the Kalman filter to be returned unchanged")
+		}
+	})
+
+	t.Run("Low-pass gap", func(t *testing.T) {
+		timed, _ := NewLowPassFilter(0.8)
+		if err := timed.SetSamplePeriod(-1); err == nil {
+			t.Error("Expected error for negative sample period")
+		}
+		if err := timed.SetSamplePeriod(0.01); err != nil {
+			t.Fatal(err)
+		}
+		reference, _ := NewLowPassFilter(0.8)
+
+		timed.EstimateAt(0.0, 0.0)
+		reference.Estimate(0.0)
+
+		// A gap of three periods matches three samples of the same measurement
+		got := timed.EstimateAt(1.0, 0.03)
+		var expected float64
+		for range 3 {
+			expected = reference.Estimate(1.0)
+		}
+		if math.Abs(got-expected) > 1e-12 {
+			t.Errorf("Expected %f after a gap, got %f", expected, got)
+		}
+		if timed.GetSamplePeriod() != 0.01 {
+			t.Errorf("Expected sample period 0.01, got %f", timed.GetSamplePeriod())
+		}
+	})
+
+	t.Run("Low-pass regular sampling matches Estimate", func(t *testing.T) {
+		timed, _ := NewLowPassFilter(0.6)
+		reference, _ := NewLowPassFilter(0.6)
+		for i := range 20 {
+			measurement := math.Sin(float64(i))
+			got := timed.EstimateAt(measurement, 5+float64(i)*0.01)
+			expected := reference.Estimate(measurement)
+			if math.Abs(got-expected) > 1e-9 {
+				t.Fatalf("Step %d: expected %f, got %f", i, expected, got)
+			}
+		}
+	})
+
+	t.Run("Default period is inferred", func(t *testing.T) {
+		// Without SetSamplePeriod a dropped sample still counts as two periods
+		timed, _ := NewLowPassFilter(0.8)
+		reference, _ := NewLowPassFilter(0.8)
+		timed.EstimateAt(0.0, 0.0)
+		timed.EstimateAt(0.0, 0.01)
+		got := timed.EstimateAt(1.0, 0.03)
+
+		reference.Estimate(0.0)
+		reference.Estimate(0.0)
+		reference.Estimate(1.0)
+		expected := reference.Estimate(1.0)
+		if math.Abs(got-expected) > 1e-12 {
+			t.Errorf("Expected %f after a dropped sample, got %f", expected, got)
+		}
+		if math.Abs(timed.GetSamplePeriod()-0.01) > 1e-15 {
+			t.Errorf("Expected inferred sample period 0.01, got %f", timed.GetSamplePeriod())
+		}
+
+		// The same for the Kalman filter, whose variance grows over the gap
+		kf, _ := NewKalmanFilter(0.1, 1.0, 5)
+		for i := range 50 {
+			kf.EstimateAt(3.0, float64(i)*0.02)
+		}
+		steady := kf.Variance()
+		kf.EstimateAt(4.0, 51*0.02)
+		if kf.Variance() <= steady {
+			t.Errorf("Expected variance to grow after a dropped sample, got %f <= %f", kf.Variance(), steady)
+		}
+
+		timed.Reset()
+		if timed.GetSamplePeriod() != 0 {
+			t.Errorf("Expected reset to forget the inferred period, got %f", timed.GetSamplePeriod())
+		}
+	})
+
+	t.Run("Late first sample does not skew the period", func(t *testing.T) {
+		// The first interval is three periods long, as when the first sample arrives late at
+		// startup, but the regular intervals that follow outvote it
+		timed, _ := NewLowPassFilter(0.8)
+		reference, _ := NewLowPassFilter(0.8)
+		timestamps := []float64{0.0, 0.03, 0.04, 0.05, 0.06, 0.07}
+		var got, expected float64
+		for i, ts := range timestamps {
+			got = timed.EstimateAt(float64(i), ts)
+			expected = reference.Estimate(float64(i))
+		}
+		if math.Abs(timed.GetSamplePeriod()-0.01) > 1e-12 {
+			t.Errorf("Expected sample period 0.01, got %f", timed.GetSamplePeriod())
+		}
+		if math.Abs(got-expected) > 1e-9 {
+			t.Errorf("Expected %f with regular samples after a late one, got %f", expected, got)
+		}
+
+		// A later dropped sample is still measured against the regular period
+		got = timed.EstimateAt(10.0, 0.09)
+		reference.Estimate(10.0)
+		expected = reference.Estimate(10.0)
+		if math.Abs(got-expected) > 1e-9 {
+			t.Errorf("Expected %f after a dropped sample, got %f", expected, got)
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			timed.GetSamplePeriod()
+		})
+		if allocs != 0 {
+			t.Errorf("Expected no allocations, got %f", allocs)
+		}
+	})
+
+	t.Run("Kalman regular sampling matches Estimate", func(t *testing.T) {
+		timed, _ := NewKalmanFilter(0.1, 1.0, 5)
+		_ = timed.SetSamplePeriod(0.02)
+		reference, _ := NewKalmanFilter(0.1, 1.0, 5)
+		for i := range 100 {
+			measurement := 0.5 * float64(i)
+			got := timed.EstimateAt(measurement, float64(i)*0.02)
+			expected := reference.Estimate(measurement)
+			if math.Abs(got-expected) > 1e-9 {
+				t.Fatalf("Step %d: expected %f, got %f", i, expected, got)
+			}
+		}
+		if math.Abs(timed.Variance()-timed.GetP()) > 1e-12 {
+			t.Errorf("Expected steady-state variance %f, got %f", timed.GetP(), timed.Variance())
+		}
+	})
+
+	t.Run("Kalman gap", func(t *testing.T) {
+		kf, _ := NewKalmanFilter(0.1, 1.0, 5)
+		_ = kf.SetSamplePeriod(0.02)
+		for i := range 100 {
+			kf.EstimateAt(3.0, float64(i)*0.02)
+		}
+		steady := kf.Variance()
+
+		// After a gap the prior uncertainty is larger, so the new measurement is trusted more
+		before := kf.GetX()
+		after := kf.EstimateAt(4.0, 99*0.02+10*0.02)
+		if kf.Variance() <= steady {
+			t.Errorf("Expected variance to grow after a gap, got %f <= %f", kf.Variance(), steady)
+		}
+		if (after-before)/(4.0-before) <= kf.GetK() {
+			t.Errorf("Expected a larger effective gain than %f after a gap", kf.GetK())
+		}
+
+		// Variance returns to steady state with regular samples
+		for i := range 100 {
+			kf.EstimateAt(4.0, 99*0.02+float64(11+i)*0.02)
+		}
+		if math.Abs(kf.Variance()-steady) > 1e-12 {
+			t.Errorf("Expected variance to return to %f, got %f", steady, kf.Variance())
+		}
+	})
+
+	t.Run("VarianceOf", func(t *testing.T) {
+		maf, _ := NewMovingAverageFilter(4)
+		for _, v := range []float64{1, 2, 3, 4} {
+			maf.Estimate(v)
+		}
+		// Sample variance 5/3 divided by 4 samples
+		if v, ok := VarianceOf(maf); !ok || math.Abs(v-5.0/12.0) > 1e-12 {
+			t.Errorf("Expected variance 5/12, got %f (%v)", v, ok)
+		}
+		if v, ok := VarianceOf(Timed(maf)); !ok || math.Abs(v-5.0/12.0) > 1e-12 {
+			t.Errorf("Expected adapter to be unwrapped, got %f (%v)", v, ok)
+		}
+
+		median, _ := NewMedianFilter(3)
+		if _, ok := VarianceOf(median); ok {
+			t.Error("Expected no variance for a median filter")
+		}
+	})
+}
@@ -0,0 +1,405 @@
+package filter
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"math/cmplx"
+	"strconv"
+
+	"control/linalg"
+)
+
+// TransferFunction is the discrete-time transfer function of a linear, time-invariant filter,
+//
+//	H(z) = (b0 + b1 z^-1 + ... + bm z^-m) / (a0 + a1 z^-1 + ... + an z^-n)
+//
+// which relates the z-transform of the estimates to that of the measurements.
+type TransferFunction struct {
+	Numerator   []float64 // Coefficients b0, b1, ... of powers of z^-1
+	Denominator []float64 // Coefficients a0, a1, ... of powers of z^-1; a0 must be non-zero
+	SampleTime  float64   // Time between samples in seconds, or 0 if unknown
+}
+
+// LinearFilter is implemented by linear, time-invariant filters that can report their transfer function.
+type LinearFilter interface {
+	Filter
+
+	// TransferFunction returns the transfer function of the filter in steady state, ignoring
+	// the start-up behavior before the filter is initialized or its window is full.
+	TransferFunction() TransferFunction
+}
+
+// TransferFunctionOf returns the transfer function of a filter. Chains and parallel
+// combinations are supported when every stage is a LinearFilter.
+//
+// Returns an error if the filter, or any stage, is not linear.
+func TransferFunctionOf(f Filter) (TransferFunction, error) {
+	switch f := f.(type) {
+	case LinearFilter:
+		return f.TransferFunction(), nil
+	case *ChainFilter:
+		result := TransferFunction{Numerator: []float64{1}, Denominator: []float64{1}}
+		for _, stage := range f.stages {
+			tf, err := TransferFunctionOf(stage)
+			if err != nil {
+				return TransferFunction{}, err
+			}
+			result = result.Series(tf)
+		}
+		return result, nil
+	case *ParallelFilter:
+		result := TransferFunction{Numerator: []float64{0}, Denominator: []float64{1}}
+		for i, stage := range f.stages {
+			tf, err := TransferFunctionOf(stage)
+			if err != nil {
+				return TransferFunction{}, err
+			}
+			result = result.Parallel(tf.Scale(f.weights[i]))
+		}
+		return result, nil
+	default:
+		return TransferFunction{}, errors.New("filter is not linear")
+	}
+}
+
+// WithSampleTime returns a copy of the transfer function with the given sample time in seconds.
+func (tf TransferFunction) WithSampleTime(sampleTime float64) TransferFunction {
+	tf.SampleTime = sampleTime
+	return tf
+}
+
+// Series returns the transfer function of tf followed by other. The sample time of tf is kept
+// unless it is zero.
+func (tf TransferFunction) Series(other TransferFunction) TransferFunction {
+	return TransferFunction{
+		Numerator:   polyMul(tf.Numerator, other.Numerator),
+		Denominator: polyMul(tf.Denominator, other.Denominator),
+		SampleTime:  firstNonZero(tf.SampleTime, other.SampleTime),
+	}
+}
+
+// Parallel returns the transfer function of the sum of the outputs of tf and other. The sample
+// time of tf is kept unless it is zero.
+func (tf TransferFunction) Parallel(other TransferFunction) TransferFunction {
+	return TransferFunction{
+		Numerator:   polyAdd(polyMul(tf.Numerator, other.Denominator), polyMul(other.Numerator, tf.Denominator)),
+		Denominator: polyMul(tf.Denominator, other.Denominator),
+		SampleTime:  firstNonZero(tf.SampleTime, other.SampleTime),
+	}
+}
+
+// Scale returns the transfer function multiplied by a constant gain.
+func (tf TransferFunction) Scale(gain float64) TransferFunction {
+	numerator := make([]float64, len(tf.Numerator))
+	for i, b := range tf.Numerator {
+		numerator[i] = gain * b
+	}
+	return TransferFunction{
+		Numerator:   numerator,
+		Denominator: append([]float64(nil), tf.Denominator...),
+		SampleTime:  tf.SampleTime,
+	}
+}
+
+// Response returns the complex frequency response H(e^jwT) at the given frequency. The frequency
+// is in hertz, or in cycles per sample if the sample time is zero.
+func (tf TransferFunction) Response(frequency float64) complex128 {
+	z := tf.unitCircle(frequency)
+	return polyEval(tf.Numerator, z) / polyEval(tf.Denominator, z)
+}
+
+// Magnitude returns the gain |H| at the given frequency.
+func (tf TransferFunction) Magnitude(frequency float64) float64 {
+	return cmplx.Abs(tf.Response(frequency))
+}
+
+// MagnitudeDB returns the gain at the given frequency in decibels.
+func (tf TransferFunction) MagnitudeDB(frequency float64) float64 {
+	return 20 * math.Log10(tf.Magnitude(frequency))
+}
+
+// Phase returns the phase of H at the given frequency in radians, in the range [-pi, pi].
+func (tf TransferFunction) Phase(frequency float64) float64 {
+	return cmplx.Phase(tf.Response(frequency))
+}
+
+// GroupDelay returns the group delay -d(phase)/d(omega) at the given frequency, the lag that a
+// narrow-band signal at that frequency experiences. It is in seconds, or in samples if the
+// sample time is zero.
+func (tf TransferFunction) GroupDelay(frequency float64) float64 {
+	z := tf.unitCircle(frequency)
+	delay := polyDelay(tf.Numerator, z) - polyDelay(tf.Denominator, z)
+	if tf.SampleTime > 0 {
+		delay *= tf.SampleTime
+	}
+	return delay
+}
+
+// DCGain returns the gain at zero frequency.
+func (tf TransferFunction) DCGain() float64 {
+	return real(tf.Response(0))
+}
+
+// unitCircle returns z = e^jwT for the frequency.
+func (tf TransferFunction) unitCircle(frequency float64) complex128 {
+	cycles := frequency
+	if tf.SampleTime > 0 {
+		cycles *= tf.SampleTime
+	}
+	return cmplx.Exp(complex(0, 2*math.Pi*cycles))
+}
+
+// BodePoint holds the frequency response of a transfer function at a single frequency.
+type BodePoint struct {
+	Frequency   float64 // Frequency in hertz, or cycles per sample if the sample time is zero
+	Magnitude   float64 // Gain |H|
+	MagnitudeDB float64 // Gain in decibels
+	Phase       float64 // Phase in degrees, unwrapped across the points
+	GroupDelay  float64 // Group delay in seconds, or samples if the sample time is zero
+}
+
+// Bode evaluates the frequency response at each frequency. The phase is unwrapped across the
+// points, so the frequencies should be in increasing order and closely enough spaced.
+func (tf TransferFunction) Bode(frequencies []float64) []BodePoint {
+	points := make([]BodePoint, len(frequencies))
+	var previous float64
+	for i, f := range frequencies {
+		response := tf.Response(f)
+		magnitude := cmplx.Abs(response)
+		phase := cmplx.Phase(response) * 180 / math.Pi
+
+		// Unwrap the phase so it is continuous across the points
+		if i > 0 {
+			phase -= 360 * math.Round((phase-previous)/360)
+		}
+		previous = phase
+
+		points[i] = BodePoint{
+			Frequency:   f,
+			Magnitude:   magnitude,
+			MagnitudeDB: 20 * math.Log10(magnitude),
+			Phase:       phase,
+			GroupDelay:  tf.GroupDelay(f),
+		}
+	}
+	return points
+}
+
+// LogSpace returns n frequencies logarithmically spaced from start to stop inclusive, for use
+// with Bode. Returns nil if n is not positive or the bounds are not positive.
+func LogSpace(start, stop float64, n int) []float64 {
+	if n <= 0 || start <= 0 || stop <= 0 {
+		return nil
+	}
+	if n == 1 {
+		return []float64{start}
+	}
+
+	frequencies := make([]float64, n)
+	ratio := math.Log(stop / start)
+	for i := range frequencies {
+		frequencies[i] = start * math.Exp(ratio*float64(i)/float64(n-1))
+	}
+	frequencies[n-1] = stop
+	return frequencies
+}
+
+// WriteBodeCSV writes Bode data as CSV with a header row of frequency, magnitude, magnitude_db,
+// phase_deg and group_delay.
+func WriteBodeCSV(w io.Writer, points []BodePoint) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"frequency", "magnitude", "magnitude_db", "phase_deg", "group_delay"}); err != nil {
+		return err
+	}
+
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	for _, p := range points {
+		record := []string{format(p.Frequency), format(p.Magnitude), format(p.MagnitudeDB), format(p.Phase), format(p.GroupDelay)}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// TransferFunction returns the transfer function (1-alpha) / (1 - alpha z^-1). The sample time
+// is the sample period used by EstimateAt, if known.
+// This implements the LinearFilter interface.
+func (lpf *LowPassFilter) TransferFunction() TransferFunction {
+	return TransferFunction{
+		Numerator:   []float64{1 - lpf.alpha},
+		Denominator: []float64{1, -lpf.alpha},
+		SampleTime:  lpf.clock.nominal(),
+	}
+}
+
+// TransferFunction returns the FIR transfer function of the average of the last N samples.
+// The sample time is unknown and left at zero.
+// This implements the LinearFilter interface.
+func (maf *MovingAverageFilter) TransferFunction() TransferFunction {
+	numerator := make([]float64, maf.size)
+	for i := range numerator {
+		numerator[i] = 1.0 / float64(maf.size)
+	}
+	return TransferFunction{Numerator: numerator, Denominator: []float64{1}}
+}
+
+// TransferFunction returns the FIR transfer function of the weighted average once the window is
+// full. The sample time is unknown and left at zero.
+// This implements the LinearFilter interface.
+func (wmaf *WeightedMovingAverageFilter) TransferFunction() TransferFunction {
+	return TransferFunction{Numerator: firTaps(wmaf.weights), Denominator: []float64{1}}
+}
+
+// TransferFunction returns the FIR transfer function of the smoothed value once the window is full.
+// This implements the LinearFilter interface.
+func (sgf *SavitzkyGolayFilter) TransferFunction() TransferFunction {
+	return TransferFunction{
+		Numerator:   firTaps(sgf.coefficients[sgf.window-1][0]),
+		Denominator: []float64{1},
+		SampleTime:  sgf.dt,
+	}
+}
+
+// TransferFunction returns the transfer function from the measurements to the position estimate.
+// This implements the LinearFilter interface.
+func (abf *AlphaBetaFilter) TransferFunction() TransferFunction {
+	dt := abf.dt
+	transition := [][]float64{
+		{1, dt},
+		{0, 1},
+	}
+	gain := []float64{abf.alpha, abf.beta / dt}
+	return trackingTransferFunction(transition, gain, dt)
+}
+
+// TransferFunction returns the transfer function from the measurements to the position estimate.
+// This implements the LinearFilter interface.
+func (abgf *AlphaBetaGammaFilter) TransferFunction() TransferFunction {
+	dt := abgf.dt
+	transition := [][]float64{
+		{1, dt, 0.5 * dt * dt},
+		{0, 1, dt},
+		{0, 0, 1},
+	}
+	gain := []float64{abgf.alpha, abgf.beta / dt, 2 * abgf.gamma / (dt * dt)}
+	return trackingTransferFunction(transition, gain, dt)
+}
+
+// trackingTransferFunction returns the transfer function from the measurement to the first state
+// of a steady-state tracking filter s[k] = (I - K H) F s[k-1] + K z[k], where H selects the first
+// state.
+//
+// With M = (I - K H) F, the transfer function is H z (zI - M)^-1 K. The characteristic
+// polynomial of M and the adjugate of zI - M are computed with the Faddeev–LeVerrier algorithm.
+func trackingTransferFunction(transition [][]float64, gain []float64, dt float64) TransferFunction {
+	n := len(gain)
+
+	// M = (I - K H) F; H selects the first state, so row r of K H F is K[r] times row 0 of F
+	m := linalg.New(n, n)
+	for r := range m {
+		for c := range m[r] {
+			m[r][c] = transition[r][c] - gain[r]*transition[0][c]
+		}
+	}
+
+	// Faddeev–LeVerrier: adj(zI - M) = sum N_k z^(n-1-k), det(zI - M) = sum c_k z^(n-k)
+	numerator := make([]float64, n+1)
+	denominator := make([]float64, n+1)
+	denominator[0] = 1
+	adjugate := linalg.Identity(n)
+	for k := 1; k <= n; k++ {
+		// The numerator coefficient of z^(n-k+1) is H N_(k-1) K, the first row of N times K
+		for c := range n {
+			numerator[k-1] += adjugate[0][c] * gain[c]
+		}
+
+		product := m.Mul(adjugate)
+		denominator[k] = -product.Trace() / float64(k)
+
+		for i := range n {
+			product[i][i] += denominator[k]
+		}
+		adjugate = product
+	}
+
+	return TransferFunction{Numerator: numerator, Denominator: denominator, SampleTime: dt}
+}
+
+// firTaps converts window coefficients ordered from oldest to newest sample into FIR taps
+// ordered by delay, normalized to sum to one.
+func firTaps(coefficients []float64) []float64 {
+	var sum float64
+	for _, c := range coefficients {
+		sum += c
+	}
+	if sum == 0 {
+		sum = 1
+	}
+
+	n := len(coefficients)
+	taps := make([]float64, n)
+	for k := range taps {
+		taps[k] = coefficients[n-1-k] / sum
+	}
+	return taps
+}
+
+// polyMul multiplies two polynomials given by their coefficients.
+func polyMul(a, b []float64) []float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	result := make([]float64, len(a)+len(b)-1)
+	for i, x := range a {
+		for j, y := range b {
+			result[i+j] += x * y
+		}
+	}
+	return result
+}
+
+// polyAdd adds two polynomials given by their coefficients.
+func polyAdd(a, b []float64) []float64 {
+	result := make([]float64, max(len(a), len(b)))
+	copy(result, a)
+	for i, y := range b {
+		result[i] += y
+	}
+	return result
+}
+
+// polyEval evaluates sum c_k z^-k.
+func polyEval(coefficients []float64, z complex128) complex128 {
+	// Horner's method in w = z^-1
+	w := 1 / z
+	var result complex128
+	for k := len(coefficients) - 1; k >= 0; k-- {
+		result = result*w + complex(coefficients[k], 0)
+	}
+	return result
+}
+
+// polyDelay returns the group delay in samples of the polynomial sum c_k z^-k on the unit
+// circle, Re(sum k c_k z^-k / sum c_k z^-k).
+func polyDelay(coefficients []float64, z complex128) float64 {
+	weighted := make([]float64, len(coefficients))
+	for k, c := range coefficients {
+		weighted[k] = float64(k) * c
+	}
+	return real(polyEval(weighted, z) / polyEval(coefficients, z))
+}
+
+// firstNonZero returns a if it is non-zero and b otherwise.
+func firstNonZero(a, b float64) float64 {
+	if a != 0 {
+		return a
+	}
+	return b
+}
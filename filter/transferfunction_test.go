@@ -0,0 +1,186 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+)
+
+// impulseResponse computes the first n samples of the impulse response of a transfer function
+// from its difference equation.
+func impulseResponse(tf TransferFunction, n int) []float64 {
+	y := make([]float64, n)
+	for k := range y {
+		if k < len(tf.Numerator) {
+			y[k] = tf.Numerator[k]
+		}
+		for i := 1; i < len(tf.Denominator) && i <= k; i++ {
+			y[k] -= tf.Denominator[i] * y[k-i]
+		}
+		y[k] /= tf.Denominator[0]
+	}
+	return y
+}
+
+// TestTransferFunction tests the transfer functions of the linear filters
+func TestTransferFunction(t *testing.T) {
+	t.Run("Impulse responses match the filters", func(t *testing.T) {
+		lpf, _ := NewLowPassFilter(0.8)
+		maf, _ := NewMovingAverageFilter(5)
+		wmaf, _ := NewWeightedMovingAverageFilter([]float64{1, 2, 4})
+		sg, _ := NewSavitzkyGolayFilter(7, 2, 0.01)
+		ab, _ := NewAlphaBetaFilter(0.5, 0.2, 0.01)
+		abg, _ := NewAlphaBetaGammaFilter(0.6, 0.3, 0.05, 0.01)
+		chainLPF, _ := NewLowPassFilter(0.5)
+		chainMAF, _ := NewMovingAverageFilter(3)
+		parallelLPF, _ := NewLowPassFilter(0.9)
+		parallelMAF, _ := NewMovingAverageFilter(4)
+		parallel, _ := Parallel([]Filter{parallelLPF, parallelMAF}, []float64{0.25, 0.75})
+
+		filters := map[string]Filter{
+			"LowPassFilter":               lpf,
+			"MovingAverageFilter":         maf,
+			"WeightedMovingAverageFilter": wmaf,
+			"SavitzkyGolayFilter":         sg,
+			"AlphaBetaFilter":             ab,
+			"AlphaBetaGammaFilter":        abg,
+			"ChainFilter":                 Chain(chainLPF, chainMAF),
+			"ParallelFilter":              parallel,
+		}
+		for name, f := range filters {
+			tf, err := TransferFunctionOf(f)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if math.Abs(tf.DCGain()-1) > 1e-9 {
+				t.Errorf("%s: expected unit DC gain, got %f", name, tf.DCGain())
+			}
+
+			// Fill the filter's window with zeros so that it is in steady state
+			for range 20 {
+				f.Estimate(0)
+			}
+			expected := impulseResponse(tf, 30)
+			for k := range expected {
+				input := 0.0
+				if k == 0 {
+					input = 1.0
+				}
+				if got := f.Estimate(input); math.Abs(got-expected[k]) > 1e-9 {
+					t.Errorf("%s: sample %d: expected %f, got %f", name, k, expected[k], got)
+					break
+				}
+			}
+		}
+	})
+
+	t.Run("Nonlinear filters", func(t *testing.T) {
+		median, _ := NewMedianFilter(3)
+		lpf, _ := NewLowPassFilter(0.5)
+		if _, err := TransferFunctionOf(median); err == nil {
+			t.Error("Expected error for a median filter")
+		}
+		if _, err := TransferFunctionOf(Chain(lpf, median)); err == nil {
+			t.Error("Expected error for a chain with a nonlinear stage")
+		}
+	})
+
+	t.Run("Low-pass response", func(t *testing.T) {
+		lpf, _ := NewLowPassFilter(0.8)
+		tf := lpf.TransferFunction()
+
+		// Without a sample time, frequencies are in cycles per sample
+		if got := tf.Magnitude(0.5); math.Abs(got-0.2/1.8) > 1e-12 {
+			t.Errorf("Expected Nyquist gain %f, got %f", 0.2/1.8, got)
+		}
+		if got := tf.GroupDelay(0); math.Abs(got-4) > 1e-9 {
+			t.Errorf("Expected DC group delay of 4 samples, got %f", got)
+		}
+
+		// At 100 Hz the lag at 5 Hz is a few samples
+		_ = lpf.SetSamplePeriod(0.01)
+		tf = lpf.TransferFunction()
+		omega := 2 * math.Pi * 5 * 0.01
+		expectedPhase := -math.Atan2(0.8*math.Sin(omega), 1-0.8*math.Cos(omega))
+		if got := tf.Phase(5); math.Abs(got-expectedPhase) > 1e-12 {
+			t.Errorf("Expected phase %f, got %f", expectedPhase, got)
+		}
+		if got := tf.GroupDelay(5); got <= 0 || got >= 0.04 {
+			t.Errorf("Expected group delay between 0 and 40 ms, got %f", got)
+		}
+		if got := tf.MagnitudeDB(5); got >= 0 {
+			t.Errorf("Expected attenuation at 5 Hz, got %f dB", got)
+		}
+	})
+
+	t.Run("Moving average has linear phase", func(t *testing.T) {
+		maf, _ := NewMovingAverageFilter(5)
+		tf := maf.TransferFunction()
+		for _, f := range []float64{0.01, 0.1, 0.15, 0.3} {
+			if got := tf.GroupDelay(f); math.Abs(got-2) > 1e-9 {
+				t.Errorf("Frequency %f: expected group delay 2, got %f", f, got)
+			}
+		}
+		if got := tf.Magnitude(0.2); got > 1e-12 {
+			t.Errorf("Expected a null at 1/N cycles per sample, got %f", got)
+		}
+		if got := tf.WithSampleTime(0.1).GroupDelay(0.1); math.Abs(got-0.2) > 1e-9 {
+			t.Errorf("Expected group delay of 0.2 s, got %f", got)
+		}
+	})
+
+	t.Run("Alpha-beta closed form", func(t *testing.T) {
+		alpha, beta := 0.5, 0.2
+		ab, _ := NewAlphaBetaFilter(alpha, beta, 0.01)
+		tf := ab.TransferFunction()
+
+		numerator := []float64{alpha, beta - alpha, 0}
+		denominator := []float64{1, alpha + beta - 2, 1 - alpha}
+		for i := range numerator {
+			if math.Abs(tf.Numerator[i]-numerator[i]) > 1e-12 {
+				t.Errorf("Numerator[%d]: expected %f, got %f", i, numerator[i], tf.Numerator[i])
+			}
+			if math.Abs(tf.Denominator[i]-denominator[i]) > 1e-12 {
+				t.Errorf("Denominator[%d]: expected %f, got %f", i, denominator[i], tf.Denominator[i])
+			}
+		}
+	})
+
+	t.Run("Bode and CSV", func(t *testing.T) {
+		// A long moving average wraps the phase many times
+		maf, _ := NewMovingAverageFilter(9)
+		tf := maf.TransferFunction().WithSampleTime(0.01)
+		frequencies := LogSpace(0.1, 10, 50)
+		if len(frequencies) != 50 || frequencies[0] != 0.1 || frequencies[49] != 10 {
+			t.Fatalf("Unexpected frequencies %v", frequencies)
+		}
+
+		points := tf.Bode(frequencies)
+		for i := 1; i < len(points); i++ {
+			if math.Abs(points[i].Phase-points[i-1].Phase) > 180 {
+				t.Errorf("Point %d: expected unwrapped phase, got %f after %f", i, points[i].Phase, points[i-1].Phase)
+			}
+		}
+		// Linear phase of 4 samples of delay at 10 Hz
+		if got := points[49].Phase; math.Abs(got+360*10*0.04) > 1e-6 {
+			t.Errorf("Expected phase %f, got %f", -360*10*0.04, got)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteBodeCSV(&buf, points); err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 51 || records[0][0] != "frequency" || len(records[1]) != 5 {
+			t.Errorf("Unexpected CSV layout: %d records, header %v", len(records), records[0])
+		}
+
+		if LogSpace(0, 1, 10) != nil || LogSpace(1, 10, 0) != nil {
+			t.Error("Expected nil for invalid ranges")
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package linalg
+
+import (
+	"math"
+	"math/cmplx"
+	"slices"
+)
+
+// ComplexColumns returns the columns of the matrix as complex vectors.
+func (m Matrix) ComplexColumns() [][]complex128 {
+	rows, cols := m.Dims()
+	result := make([][]complex128, max(cols, 0))
+	for j := range result {
+		result[j] = make([]complex128, rows)
+		for i := range rows {
+			result[j][i] = complex(m[i][j], 0)
+		}
+	}
+	return result
+}
+
+// OrthogonalComplement returns an orthonormal basis of the orthogonal complement of the span
+// of the given vectors in C^n.
+func OrthogonalComplement(vectors [][]complex128, n int) [][]complex128 {
+	basis := make([][]complex128, 0, n)
+	for _, v := range vectors {
+		if len(basis) == n {
+			break
+		}
+		if u, ok := orthonormalize(v, basis); ok {
+			basis = append(basis, u)
+		}
+	}
+	spanned := len(basis)
+	for i := 0; i < n && len(basis) < n; i++ {
+		e := make([]complex128, n)
+		e[i] = 1
+		if u, ok := orthonormalize(e, basis); ok {
+			basis = append(basis, u)
+		}
+	}
+	return basis[spanned:]
+}
+
+// orthonormalize removes the components of v along an orthonormal basis using modified
+// Gram-Schmidt with reorthogonalization, and normalizes the remainder. It reports false if v is
+// numerically in the span of the basis.
+func orthonormalize(v []complex128, basis [][]complex128) ([]complex128, bool) {
+	u := slices.Clone(v)
+	original := VectorNorm(u)
+	if original == 0 {
+		return nil, false
+	}
+	for range 2 {
+		for _, b := range basis {
+			c := InnerProduct(b, u)
+			for i := range u {
+				u[i] -= c * b[i]
+			}
+		}
+	}
+	norm := VectorNorm(u)
+	if norm <= 1e-9*original {
+		return nil, false
+	}
+	for i := range u {
+		u[i] /= complex(norm, 0)
+	}
+	return u, true
+}
+
+// InnerProduct returns the Hermitian inner product of u and v, conjugating u.
+func InnerProduct(u, v []complex128) complex128 {
+	var sum complex128
+	for i := range u {
+		sum += cmplx.Conj(u[i]) * v[i]
+	}
+	return sum
+}
+
+// VectorNorm returns the Euclidean norm of a complex vector.
+func VectorNorm(v []complex128) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += real(x)*real(x) + imag(x)*imag(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// SolveComplex returns X such that m * X = b for square complex m, using Gaussian elimination
+// with partial pivoting. Both arguments are overwritten. Returns ErrSingularMatrix if m is
+// singular to working precision.
+func SolveComplex(m, b [][]complex128) ([][]complex128, error) {
+	n := len(m)
+	var scale float64
+	for _, row := range m {
+		for _, v := range row {
+			scale = max(scale, cmplx.Abs(v))
+		}
+	}
+	tolerance := 1e-12 * max(scale, 1e-300)
+
+	for col := range n {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if cmplx.Abs(m[row][col]) > cmplx.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if cmplx.Abs(m[pivot][col]) <= tolerance {
+			return nil, ErrSingularMatrix
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := m[row][col] / m[col][col]
+			if factor == 0 {
+				continue
+			}
+			for c := col; c < n; c++ {
+				m[row][c] -= factor * m[col][c]
+			}
+			for c := range b[row] {
+				b[row][c] -= factor * b[col][c]
+			}
+		}
+	}
+
+	// Back substitution
+	for row := n - 1; row >= 0; row-- {
+		for c := range b[row] {
+			sum := b[row][c]
+			for k := row + 1; k < n; k++ {
+				sum -= m[row][k] * b[k][c]
+			}
+			b[row][c] = sum / m[row][row]
+		}
+	}
+	return b, nil
+}
+
+// Conjugate returns the element-wise complex conjugate of a vector.
+func Conjugate(v []complex128) []complex128 {
+	result := make([]complex128, len(v))
+	for i, x := range v {
+		result[i] = cmplx.Conj(x)
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestOrthogonalComplement(t *testing.T) {
+	vectors := [][]complex128{{1, 1i, 0}}
+	complement := OrthogonalComplement(vectors, 3)
+	if len(complement) != 2 {
+		t.Fatalf("Expected a 2-dimensional complement, got %d", len(complement))
+	}
+	for i, u := range complement {
+		if math.Abs(VectorNorm(u)-1) > 1e-12 {
+			t.Errorf("Vector %d has norm %f", i, VectorNorm(u))
+		}
+		if cmplx.Abs(InnerProduct(vectors[0], u)) > 1e-12 {
+			t.Errorf("Vector %d is not orthogonal to the input", i)
+		}
+	}
+	if cmplx.Abs(InnerProduct(complement[0], complement[1])) > 1e-12 {
+		t.Error("Expected the complement to be orthonormal")
+	}
+
+	// Dependent vectors span less than their number
+	dependent := [][]complex128{{1, 2}, {2, 4}}
+	if got := len(OrthogonalComplement(dependent, 2)); got != 1 {
+		t.Errorf("Expected a 1-dimensional complement, got %d", got)
+	}
+}
+
+func TestSolveComplex(t *testing.T) {
+	m := [][]complex128{{0, 1i}, {2, 1}}
+	b := [][]complex128{{1i}, {3}}
+	x, err := SolveComplex(m, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmplx.Abs(x[0][0]-1) > 1e-12 || cmplx.Abs(x[1][0]-1) > 1e-12 {
+		t.Errorf("Expected [1 1], got %v", x)
+	}
+
+	if _, err := SolveComplex([][]complex128{{1, 1i}, {1i, -1}}, [][]complex128{{1}, {1}}); !errors.Is(err, ErrSingularMatrix) {
+		t.Errorf("Expected ErrSingularMatrix, got %v", err)
+	}
+
+	if got := Conjugate([]complex128{1 + 2i, -1i}); got[0] != 1-2i || got[1] != 1i {
+		t.Errorf("Expected the conjugate, got %v", got)
+	}
+	if got := (Matrix{{1, 2}, {3, 4}}).ComplexColumns(); got[1][0] != 2 || got[0][1] != 3 {
+		t.Errorf("Expected the columns, got %v", got)
+	}
+}
@@ -0,0 +1,293 @@
+package linalg
+
+import (
+	"math"
+)
+
+// Eigenvalues returns the eigenvalues of a square matrix. The matrix is balanced, reduced to
+// upper Hessenberg form by stabilized elimination, and the eigenvalues found with the shifted
+// QR algorithm. Complex eigenvalues are returned as adjacent conjugate pairs.
+//
+// Returns ErrDimensionMismatch if the matrix is not square and ErrNoConvergence if the QR
+// iteration fails to converge.
+func (m Matrix) Eigenvalues() ([]complex128, error) {
+	if !m.IsSquare() {
+		return nil, ErrDimensionMismatch
+	}
+	n, _ := m.Dims()
+
+	// Work on a 1-indexed copy to follow the classic formulation of the algorithms
+	a := New(n+1, n+1)
+	for i := range n {
+		copy(a[i+1][1:], m[i])
+	}
+	balance(a, n)
+	hessenberg(a, n)
+	return hessenbergEigenvalues(a, n)
+}
+
+// balance scales the rows and columns of the 1-indexed matrix by powers of two to make their
+// norms similar, which improves the accuracy of the eigenvalues.
+func balance(a Matrix, n int) {
+	const radix = 2.0
+	done := false
+	for !done {
+		done = true
+		for i := 1; i <= n; i++ {
+			var r, c float64
+			for j := 1; j <= n; j++ {
+				if j != i {
+					c += math.Abs(a[j][i])
+					r += math.Abs(a[i][j])
+				}
+			}
+			if c == 0 || r == 0 {
+				continue
+			}
+			g := r / radix
+			f := 1.0
+			s := c + r
+			for c < g {
+				f *= radix
+				c *= radix * radix
+			}
+			g = r * radix
+			for c > g {
+				f /= radix
+				c /= radix * radix
+			}
+			if (c+r)/f < 0.95*s {
+				done = false
+				g = 1 / f
+				for j := 1; j <= n; j++ {
+					a[i][j] *= g
+					a[j][i] *= f
+				}
+			}
+		}
+	}
+}
+
+// hessenberg reduces the 1-indexed matrix to upper Hessenberg form by Gaussian elimination
+// with pivoting, which is a similarity transformation.
+func hessenberg(a Matrix, n int) {
+	for m := 2; m < n; m++ {
+		x := 0.0
+		pivot := m
+		for j := m; j <= n; j++ {
+			if math.Abs(a[j][m-1]) > math.Abs(x) {
+				x = a[j][m-1]
+				pivot = j
+			}
+		}
+		if pivot != m {
+			for j := m - 1; j <= n; j++ {
+				a[pivot][j], a[m][j] = a[m][j], a[pivot][j]
+			}
+			for j := 1; j <= n; j++ {
+				a[j][pivot], a[j][m] = a[j][m], a[j][pivot]
+			}
+		}
+		if x == 0 {
+			continue
+		}
+		for i := m + 1; i <= n; i++ {
+			y := a[i][m-1]
+			if y == 0 {
+				continue
+			}
+			y /= x
+			a[i][m-1] = 0
+			for j := m; j <= n; j++ {
+				a[i][j] -= y * a[m][j]
+			}
+			for j := 1; j <= n; j++ {
+				a[j][m] += y * a[j][i]
+			}
+		}
+	}
+}
+
+// hessenbergEigenvalues returns the eigenvalues of a 1-indexed upper Hessenberg matrix using
+// the Francis double-shift QR algorithm. The matrix is destroyed.
+func hessenbergEigenvalues(a Matrix, n int) ([]complex128, error) {
+	const maxIterations = 30
+	wr := make([]float64, n+1)
+	wi := make([]float64, n+1)
+
+	var anorm float64
+	for i := 1; i <= n; i++ {
+		for j := max(i-1, 1); j <= n; j++ {
+			anorm += math.Abs(a[i][j])
+		}
+	}
+
+	nn := n
+	t := 0.0
+	for nn >= 1 {
+		iterations := 0
+		l := 0
+		for {
+			// Look for a single small subdiagonal element
+			for l = nn; l >= 2; l-- {
+				s := math.Abs(a[l-1][l-1]) + math.Abs(a[l][l])
+				if s == 0 {
+					s = anorm
+				}
+				if math.Abs(a[l][l-1])+s == s {
+					a[l][l-1] = 0
+					break
+				}
+			}
+
+			x := a[nn][nn]
+			if l == nn {
+				// One root found
+				wr[nn] = x + t
+				wi[nn] = 0
+				nn--
+			} else {
+				y := a[nn-1][nn-1]
+				w := a[nn][nn-1] * a[nn-1][nn]
+				if l == nn-1 {
+					// Two roots found
+					p := 0.5 * (y - x)
+					q := p*p + w
+					z := math.Sqrt(math.Abs(q))
+					x += t
+					if q >= 0 {
+						z = p + math.Copysign(z, p)
+						wr[nn-1] = x + z
+						wr[nn] = x + z
+						if z != 0 {
+							wr[nn] = x - w/z
+						}
+						wi[nn-1], wi[nn] = 0, 0
+					} else {
+						wr[nn-1] = x + p
+						wr[nn] = x + p
+						wi[nn-1] = -z
+						wi[nn] = z
+					}
+					nn -= 2
+				} else {
+					if iterations == maxIterations {
+						return nil, ErrNoConvergence
+					}
+					if iterations == 10 || iterations == 20 {
+						// Exceptional shift
+						t += x
+						for i := 1; i <= nn; i++ {
+							a[i][i] -= x
+						}
+						s := math.Abs(a[nn][nn-1]) + math.Abs(a[nn-1][nn-2])
+						x = 0.75 * s
+						y = x
+						w = -0.4375 * s * s
+					}
+					iterations++
+					francisStep(a, l, nn, x, y, w)
+				}
+			}
+			if l >= nn-1 {
+				break
+			}
+		}
+	}
+
+	result := make([]complex128, n)
+	for i := range n {
+		result[i] = complex(wr[i+1], wi[i+1])
+	}
+	return result, nil
+}
+
+// francisStep performs one double-shift QR step on rows and columns l to nn of a 1-indexed
+// Hessenberg matrix, with shifts determined by x, y and w.
+func francisStep(a Matrix, l, nn int, x, y, w float64) {
+	var p, q, r, z float64
+
+	// Look for two consecutive small subdiagonal elements
+	m := nn - 2
+	for ; m >= l; m-- {
+		z = a[m][m]
+		r = x - z
+		s := y - z
+		p = (r*s-w)/a[m+1][m] + a[m][m+1]
+		q = a[m+1][m+1] - z - r - s
+		r = a[m+2][m+1]
+		s = math.Abs(p) + math.Abs(q) + math.Abs(r)
+		p /= s
+		q /= s
+		r /= s
+		if m == l {
+			break
+		}
+		u := math.Abs(a[m][m-1]) * (math.Abs(q) + math.Abs(r))
+		v := math.Abs(p) * (math.Abs(a[m-1][m-1]) + math.Abs(z) + math.Abs(a[m+1][m+1]))
+		if u+v == v {
+			break
+		}
+	}
+	for i := m + 2; i <= nn; i++ {
+		a[i][i-2] = 0
+		if i != m+2 {
+			a[i][i-3] = 0
+		}
+	}
+
+	for k := m; k <= nn-1; k++ {
+		if k != m {
+			p = a[k][k-1]
+			q = a[k+1][k-1]
+			r = 0
+			if k != nn-1 {
+				r = a[k+2][k-1]
+			}
+			if x = math.Abs(p) + math.Abs(q) + math.Abs(r); x != 0 {
+				p /= x
+				q /= x
+				r /= x
+			}
+		}
+		s := math.Copysign(math.Sqrt(p*p+q*q+r*r), p)
+		if s == 0 {
+			continue
+		}
+		if k == m {
+			if l != m {
+				a[k][k-1] = -a[k][k-1]
+			}
+		} else {
+			a[k][k-1] = -s * x
+		}
+		p += s
+		x = p / s
+		y = q / s
+		z = r / s
+		q /= p
+		r /= p
+
+		// Row modification
+		for j := k; j <= nn; j++ {
+			p = a[k][j] + q*a[k+1][j]
+			if k != nn-1 {
+				p += r * a[k+2][j]
+				a[k+2][j] -= p * z
+			}
+			a[k+1][j] -= p * y
+			a[k][j] -= p * x
+		}
+
+		// Column modification
+		for i := l; i <= min(nn, k+3); i++ {
+			p = x*a[i][k] + y*a[i][k+1]
+			if k != nn-1 {
+				p += z * a[i][k+2]
+				a[i][k+2] -= p * r
+			}
+			a[i][k+1] -= p * q
+			a[i][k] -= p
+		}
+	}
+}
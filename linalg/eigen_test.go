@@ -0,0 +1,44 @@
+package linalg
+
+import (
+	"cmp"
+	"errors"
+	"math/cmplx"
+	"slices"
+	"testing"
+)
+
+func TestEigenvalues(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Matrix
+		expected []complex128
+	}{
+		{"Diagonal", Diagonal(3, -1, 2), []complex128{-1, 2, 3}},
+		{"Triangular", Matrix{{1, 5, 7}, {0, 2, 3}, {0, 0, 4}}, []complex128{1, 2, 4}},
+		{"Rotation", Matrix{{0, 1}, {-1, 0}}, []complex128{-1i, 1i}},
+		{"Damped oscillator", Matrix{{0, 1}, {-5, -2}}, []complex128{-1 - 2i, -1 + 2i}},
+		{"Companion", Matrix{{0, 1, 0}, {0, 0, 1}, {6, -11, 6}}, []complex128{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		got, err := tt.m.Eigenvalues()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		slices.SortFunc(got, func(a, b complex128) int {
+			return cmp.Or(cmp.Compare(real(a), real(b)), cmp.Compare(imag(a), imag(b)))
+		})
+		if len(got) != len(tt.expected) {
+			t.Fatalf("%s: expected %d eigenvalues, got %v", tt.name, len(tt.expected), got)
+		}
+		for i, e := range tt.expected {
+			if cmplx.Abs(got[i]-e) > 1e-9 {
+				t.Errorf("%s: eigenvalue %d = %v, expected %v", tt.name, i, got[i], e)
+			}
+		}
+	}
+
+	if _, err := (Matrix{{1, 2}}).Eigenvalues(); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+package linalg
+
+import "errors"
+
+var (
+	ErrDimensionMismatch = errors.New("matrix dimensions do not match")
+	ErrSingularMatrix    = errors.New("matrix is singular")
+	ErrNoConvergence     = errors.New("eigenvalue iteration did not converge")
+)
@@ -0,0 +1,47 @@
+package linalg
+
+import (
+	"math"
+)
+
+// Exp returns the matrix exponential of a square matrix, computed by scaling and squaring with
+// a diagonal Pade approximation.
+//
+// Returns ErrDimensionMismatch if the matrix is not square.
+func (m Matrix) Exp() (Matrix, error) {
+	if !m.IsSquare() {
+		return nil, ErrDimensionMismatch
+	}
+	n, _ := m.Dims()
+	squarings := 0
+	if norm := m.Norm(); norm > 0.5 {
+		squarings = int(math.Ceil(math.Log2(norm / 0.5)))
+	}
+	x := m.Scale(math.Ldexp(1, -squarings))
+
+	// Pade approximation of degree 6, accurate to double precision for ||X|| <= 0.5
+	const degree = 6
+	identity := Identity(n)
+	numerator := identity.Clone()
+	denominator := identity.Clone()
+	power := identity
+	c := 1.0
+	for k := 1; k <= degree; k++ {
+		c *= float64(degree-k+1) / float64(k*(2*degree-k+1))
+		power = power.Mul(x)
+		numerator = numerator.Add(power.Scale(c))
+		if k%2 == 0 {
+			denominator = denominator.Add(power.Scale(c))
+		} else {
+			denominator = denominator.Sub(power.Scale(c))
+		}
+	}
+	result, err := denominator.Solve(numerator)
+	if err != nil {
+		return nil, err
+	}
+	for range squarings {
+		result = result.Mul(result)
+	}
+	return result, nil
+}
@@ -0,0 +1,35 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestExp(t *testing.T) {
+	t.Run("Rotation", func(t *testing.T) {
+		// exp of a rotation generator is a rotation, including for large arguments
+		for _, angle := range []float64{0, 0.3, 2, 40} {
+			got, err := Matrix{{0, angle}, {-angle, 0}}.Exp()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := Matrix{{math.Cos(angle), math.Sin(angle)}, {-math.Sin(angle), math.Cos(angle)}}
+			assertMatrix(t, "exp", got, expected, 1e-11)
+		}
+	})
+
+	t.Run("Nilpotent", func(t *testing.T) {
+		got, err := Matrix{{0, 1}, {0, 0}}.Exp()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertMatrix(t, "exp", got, Matrix{{1, 1}, {0, 1}}, 1e-14)
+	})
+
+	t.Run("Not square", func(t *testing.T) {
+		if _, err := (Matrix{{1, 2}}).Exp(); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+}
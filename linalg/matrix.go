@@ -0,0 +1,234 @@
+package linalg
+
+import (
+	"math"
+)
+
+// Matrix is a dense matrix stored as a slice of rows.
+//
+// Arithmetic methods return new matrices and leave their operands unchanged. They panic with
+// ErrDimensionMismatch if the operands do not fit, as indexing out of range would.
+type Matrix [][]float64
+
+// New returns a zero matrix with the given dimensions. The rows share one backing array.
+func New(rows, cols int) Matrix {
+	data := make([]float64, rows*cols)
+	m := make(Matrix, rows)
+	for i := range m {
+		m[i] = data[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return m
+}
+
+// Identity returns the n by n identity matrix.
+func Identity(n int) Matrix {
+	m := New(n, n)
+	for i := range n {
+		m[i][i] = 1
+	}
+	return m
+}
+
+// Diagonal returns a square matrix with the given values on its diagonal.
+func Diagonal(values ...float64) Matrix {
+	m := New(len(values), len(values))
+	for i, v := range values {
+		m[i][i] = v
+	}
+	return m
+}
+
+// Dims returns the number of rows and columns of the matrix. A matrix with rows of different
+// lengths reports -1 columns.
+func (m Matrix) Dims() (rows, cols int) {
+	rows = len(m)
+	if rows == 0 {
+		return 0, 0
+	}
+	cols = len(m[0])
+	for _, row := range m {
+		if len(row) != cols {
+			return rows, -1
+		}
+	}
+	return rows, cols
+}
+
+// IsSquare reports whether the matrix is non-empty and has as many columns as rows.
+func (m Matrix) IsSquare() bool {
+	rows, cols := m.Dims()
+	return rows > 0 && rows == cols
+}
+
+// Clone returns a deep copy of the matrix. Panics with ErrDimensionMismatch if the rows have
+// different lengths.
+func (m Matrix) Clone() Matrix {
+	rows, cols := m.Dims()
+	if cols < 0 {
+		panic(ErrDimensionMismatch)
+	}
+	result := New(rows, cols)
+	for i := range m {
+		copy(result[i], m[i])
+	}
+	return result
+}
+
+// Mul returns the matrix product m * other.
+func (m Matrix) Mul(other Matrix) Matrix {
+	rows, inner := m.Dims()
+	otherRows, cols := other.Dims()
+	if inner != otherRows || inner < 0 || cols < 0 {
+		panic(ErrDimensionMismatch)
+	}
+	result := New(rows, cols)
+	for i := range rows {
+		for k := range inner {
+			a := m[i][k]
+			if a == 0 {
+				continue
+			}
+			for j := range cols {
+				result[i][j] += a * other[k][j]
+			}
+		}
+	}
+	return result
+}
+
+// MulVec writes the matrix-vector product m * v into dst, which must have one element per row,
+// and returns it. It does not allocate.
+func (m Matrix) MulVec(dst, v []float64) []float64 {
+	if len(dst) != len(m) {
+		panic(ErrDimensionMismatch)
+	}
+	for i, row := range m {
+		dst[i] = Dot(row, v)
+	}
+	return dst
+}
+
+// Add returns the element-wise sum m + other.
+func (m Matrix) Add(other Matrix) Matrix {
+	m.checkSameDims(other)
+	result := m.Clone()
+	for i := range result {
+		for j := range result[i] {
+			result[i][j] += other[i][j]
+		}
+	}
+	return result
+}
+
+// Sub returns the element-wise difference m - other.
+func (m Matrix) Sub(other Matrix) Matrix {
+	m.checkSameDims(other)
+	result := m.Clone()
+	for i := range result {
+		for j := range result[i] {
+			result[i][j] -= other[i][j]
+		}
+	}
+	return result
+}
+
+// Scale returns the matrix multiplied by a scalar.
+func (m Matrix) Scale(s float64) Matrix {
+	result := m.Clone()
+	for i := range result {
+		for j := range result[i] {
+			result[i][j] *= s
+		}
+	}
+	return result
+}
+
+// Transpose returns the transpose of the matrix. Panics with ErrDimensionMismatch if the rows
+// have different lengths.
+func (m Matrix) Transpose() Matrix {
+	rows, cols := m.Dims()
+	if cols < 0 {
+		panic(ErrDimensionMismatch)
+	}
+	result := New(cols, rows)
+	for i := range rows {
+		for j := range cols {
+			result[j][i] = m[i][j]
+		}
+	}
+	return result
+}
+
+// Symmetrize returns (m + m^T) / 2, removing the asymmetry that rounding introduces into
+// matrices that should be symmetric. The matrix must be square.
+func (m Matrix) Symmetrize() Matrix {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	result := m.Clone()
+	for i := range result {
+		for j := range i {
+			v := (m[i][j] + m[j][i]) / 2
+			result[i][j], result[j][i] = v, v
+		}
+	}
+	return result
+}
+
+// Trace returns the sum of the diagonal elements of a square matrix.
+func (m Matrix) Trace() float64 {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	var sum float64
+	for i := range m {
+		sum += m[i][i]
+	}
+	return sum
+}
+
+// Norm returns the maximum absolute row sum (infinity norm) of the matrix.
+func (m Matrix) Norm() float64 {
+	var result float64
+	for _, row := range m {
+		var sum float64
+		for _, v := range row {
+			sum += math.Abs(v)
+		}
+		result = max(result, sum)
+	}
+	return result
+}
+
+// IsFinite reports whether every element is finite.
+func (m Matrix) IsFinite() bool {
+	for _, row := range m {
+		for _, v := range row {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Dot returns the dot product of two vectors of the same length.
+func Dot(a, b []float64) float64 {
+	if len(a) != len(b) {
+		panic(ErrDimensionMismatch)
+	}
+	var sum float64
+	for i, v := range a {
+		sum += v * b[i]
+	}
+	return sum
+}
+
+// checkSameDims panics if the matrices do not have the same dimensions.
+func (m Matrix) checkSameDims(other Matrix) {
+	rows, cols := m.Dims()
+	otherRows, otherCols := other.Dims()
+	if rows != otherRows || cols != otherCols || cols < 0 {
+		panic(ErrDimensionMismatch)
+	}
+}
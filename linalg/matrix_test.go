@@ -0,0 +1,145 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func assertMatrix(t *testing.T, name string, got, expected Matrix, tolerance float64) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("%s: expected %d rows, got %d", name, len(expected), len(got))
+	}
+	for i := range expected {
+		if len(got[i]) != len(expected[i]) {
+			t.Fatalf("%s: expected %d columns in row %d, got %d", name, len(expected[i]), i, len(got[i]))
+		}
+		for j := range expected[i] {
+			if math.Abs(got[i][j]-expected[i][j]) > tolerance {
+				t.Errorf("%s[%d][%d] = %f, expected %f", name, i, j, got[i][j], expected[i][j])
+			}
+		}
+	}
+}
+
+func assertPanics(t *testing.T, name string, expected error, f func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		r := recover()
+		if err, ok := r.(error); !ok || !errors.Is(err, expected) {
+			t.Errorf("%s: expected panic with %v, got %v", name, expected, r)
+		}
+	}()
+	f()
+}
+
+func TestMatrix(t *testing.T) {
+	a := Matrix{{1, 2}, {3, 4}}
+	b := Matrix{{0, 1}, {1, 0}}
+
+	t.Run("Construction", func(t *testing.T) {
+		assertMatrix(t, "New", New(2, 3), Matrix{{0, 0, 0}, {0, 0, 0}}, 0)
+		assertMatrix(t, "Identity", Identity(2), Matrix{{1, 0}, {0, 1}}, 0)
+		assertMatrix(t, "Diagonal", Diagonal(2, 3), Matrix{{2, 0}, {0, 3}}, 0)
+
+		// Appending to a row must not overwrite the next one
+		m := New(2, 2)
+		_ = append(m[0], 5)
+		if m[1][0] != 0 {
+			t.Errorf("Expected rows to be independent, got %v", m)
+		}
+	})
+
+	t.Run("Dims", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			m          Matrix
+			rows, cols int
+			square     bool
+		}{
+			{"Empty", nil, 0, 0, false},
+			{"Square", a, 2, 2, true},
+			{"Wide", Matrix{{1, 2, 3}}, 1, 3, false},
+			{"Ragged", Matrix{{1, 2}, {3}}, 2, -1, false},
+		}
+		for _, tt := range tests {
+			rows, cols := tt.m.Dims()
+			if rows != tt.rows || cols != tt.cols || tt.m.IsSquare() != tt.square {
+				t.Errorf("%s: got %dx%d square %v", tt.name, rows, cols, tt.m.IsSquare())
+			}
+		}
+	})
+
+	t.Run("Arithmetic", func(t *testing.T) {
+		assertMatrix(t, "Mul", a.Mul(b), Matrix{{2, 1}, {4, 3}}, 0)
+		assertMatrix(t, "Mul wide", Matrix{{1, 2, 3}}.Mul(Matrix{{1}, {1}, {1}}), Matrix{{6}}, 0)
+		assertMatrix(t, "Add", a.Add(b), Matrix{{1, 3}, {4, 4}}, 0)
+		assertMatrix(t, "Sub", a.Sub(b), Matrix{{1, 1}, {2, 4}}, 0)
+		assertMatrix(t, "Scale", a.Scale(2), Matrix{{2, 4}, {6, 8}}, 0)
+		assertMatrix(t, "Transpose", Matrix{{1, 2, 3}}.Transpose(), Matrix{{1}, {2}, {3}}, 0)
+		assertMatrix(t, "Symmetrize", a.Symmetrize(), Matrix{{1, 2.5}, {2.5, 4}}, 0)
+
+		// Operands are left unchanged
+		assertMatrix(t, "a", a, Matrix{{1, 2}, {3, 4}}, 0)
+
+		if got := a.Trace(); got != 5 {
+			t.Errorf("Expected trace 5, got %f", got)
+		}
+		if got := a.Norm(); got != 7 {
+			t.Errorf("Expected norm 7, got %f", got)
+		}
+		if got := Dot([]float64{1, 2, 3}, []float64{4, 5, 6}); got != 32 {
+			t.Errorf("Expected dot product 32, got %f", got)
+		}
+	})
+
+	t.Run("MulVec", func(t *testing.T) {
+		dst := make([]float64, 2)
+		a.MulVec(dst, []float64{1, -1})
+		if dst[0] != -1 || dst[1] != -1 {
+			t.Errorf("Expected [-1 -1], got %v", dst)
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			a.MulVec(dst, []float64{1, -1})
+		})
+		if allocs != 0 {
+			t.Errorf("Expected no allocations, got %f", allocs)
+		}
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		c := a.Clone()
+		c[0][0] = 10
+		if a[0][0] != 1 {
+			t.Error("Expected Clone to copy the elements")
+		}
+	})
+
+	t.Run("IsFinite", func(t *testing.T) {
+		if !a.IsFinite() {
+			t.Error("Expected a finite matrix")
+		}
+		if (Matrix{{1, math.NaN()}}).IsFinite() || (Matrix{{math.Inf(-1)}}).IsFinite() {
+			t.Error("Expected NaN and Inf to be detected")
+		}
+	})
+
+	t.Run("Dimension mismatch", func(t *testing.T) {
+		wide := Matrix{{1, 2, 3}}
+		assertPanics(t, "Mul", ErrDimensionMismatch, func() { a.Mul(wide.Transpose()) })
+		assertPanics(t, "Add", ErrDimensionMismatch, func() { a.Add(wide) })
+		assertPanics(t, "Sub", ErrDimensionMismatch, func() { a.Sub(wide) })
+		assertPanics(t, "Trace", ErrDimensionMismatch, func() { wide.Trace() })
+		assertPanics(t, "Symmetrize", ErrDimensionMismatch, func() { wide.Symmetrize() })
+		assertPanics(t, "MulVec", ErrDimensionMismatch, func() { a.MulVec(make([]float64, 1), []float64{1, 2}) })
+		assertPanics(t, "Dot", ErrDimensionMismatch, func() { Dot([]float64{1}, []float64{1, 2}) })
+
+		ragged := Matrix{{1, 2}, {3}}
+		assertPanics(t, "Clone ragged", ErrDimensionMismatch, func() { ragged.Clone() })
+		assertPanics(t, "Transpose ragged", ErrDimensionMismatch, func() { ragged.Transpose() })
+		assertPanics(t, "Scale ragged", ErrDimensionMismatch, func() { ragged.Scale(2) })
+		assertPanics(t, "Add ragged", ErrDimensionMismatch, func() { ragged.Add(ragged) })
+	})
+}
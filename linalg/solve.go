@@ -0,0 +1,172 @@
+package linalg
+
+import (
+	"math"
+)
+
+// pivotTolerance is the size of a pivot, relative to the largest element of the matrix, below
+// which the matrix is treated as singular
+const pivotTolerance = 1e-12
+
+// Solve returns X such that m * X = b, using Gaussian elimination with partial pivoting.
+//
+// Returns ErrDimensionMismatch if m is not square or b has a different number of rows, and
+// ErrSingularMatrix if m is singular to working precision.
+func (m Matrix) Solve(b Matrix) (Matrix, error) {
+	n, _ := m.Dims()
+	rows, cols := b.Dims()
+	if !m.IsSquare() || rows != n || cols < 0 {
+		return nil, ErrDimensionMismatch
+	}
+	a := m.Clone()
+	x := b.Clone()
+
+	tolerance := pivotTolerance * maxAbs(a, n)
+	for col := range n {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) <= tolerance {
+			return nil, ErrSingularMatrix
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		x[col], x[pivot] = x[pivot], x[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			if factor == 0 {
+				continue
+			}
+			for c := col; c < n; c++ {
+				a[row][c] -= factor * a[col][c]
+			}
+			for c := range cols {
+				x[row][c] -= factor * x[col][c]
+			}
+		}
+	}
+
+	// Back substitution
+	for row := n - 1; row >= 0; row-- {
+		for c := range cols {
+			sum := x[row][c]
+			for k := row + 1; k < n; k++ {
+				sum -= a[row][k] * x[k][c]
+			}
+			x[row][c] = sum / a[row][row]
+		}
+	}
+	return x, nil
+}
+
+// SolveInPlace solves a x = b for a single right-hand side without allocating, overwriting b
+// with the solution. Only the leading len(b) by len(b) block of a is used, so a larger scratch
+// matrix can be reused for smaller systems. The block is destroyed and the rows of a may be
+// reordered.
+//
+// Returns ErrDimensionMismatch if a is too small and ErrSingularMatrix if the block is singular
+// to working precision, in which case b is left in an unspecified state.
+func SolveInPlace(a Matrix, b []float64) error {
+	n := len(b)
+	if len(a) < n {
+		return ErrDimensionMismatch
+	}
+	for _, row := range a[:n] {
+		if len(row) < n {
+			return ErrDimensionMismatch
+		}
+	}
+
+	tolerance := pivotTolerance * maxAbs(a, n)
+	for col := range n {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) <= tolerance {
+			return ErrSingularMatrix
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			b[row] -= factor * b[col]
+		}
+	}
+
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * b[k]
+		}
+		b[row] = sum / a[row][row]
+	}
+	return nil
+}
+
+// Inverse returns the inverse of a square matrix.
+//
+// Returns ErrDimensionMismatch if the matrix is not square and ErrSingularMatrix if it is
+// singular to working precision.
+func (m Matrix) Inverse() (Matrix, error) {
+	n, _ := m.Dims()
+	return m.Solve(Identity(n))
+}
+
+// LogDet returns the natural logarithm of the absolute value of the determinant of a square
+// matrix, computed by LU decomposition. Returns -Inf for a singular matrix.
+func (m Matrix) LogDet() float64 {
+	if !m.IsSquare() {
+		panic(ErrDimensionMismatch)
+	}
+	n, _ := m.Dims()
+	a := m.Clone()
+	var result float64
+	for col := range n {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if a[pivot][col] == 0 {
+			return math.Inf(-1)
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		result += math.Log(math.Abs(a[col][col]))
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for c := col; c < n; c++ {
+				a[row][c] -= factor * a[col][c]
+			}
+		}
+	}
+	return result
+}
+
+// Rank returns the numerical rank of the matrix, the number of linearly independent columns.
+func (m Matrix) Rank() int {
+	rows, _ := m.Dims()
+	return rows - len(OrthogonalComplement(m.ComplexColumns(), rows))
+}
+
+// maxAbs returns the largest absolute element of the leading n by n block.
+func maxAbs(a Matrix, n int) float64 {
+	var result float64
+	for _, row := range a[:n] {
+		for _, v := range row[:n] {
+			result = max(result, math.Abs(v))
+		}
+	}
+	return result
+}
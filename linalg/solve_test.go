@@ -0,0 +1,114 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	t.Run("Needs pivoting", func(t *testing.T) {
+		a := Matrix{{0, 1, 2}, {1, 0, 3}, {4, -3, 8}}
+		b := Matrix{{1, 0}, {0, 1}, {2, 3}}
+		x, err := a.Solve(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertMatrix(t, "A X", a.Mul(x), b, 1e-12)
+	})
+
+	t.Run("Inverse", func(t *testing.T) {
+		a := Matrix{{4, 7}, {2, 6}}
+		inverse, err := a.Inverse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertMatrix(t, "inverse", inverse, Matrix{{0.6, -0.7}, {-0.2, 0.4}}, 1e-12)
+	})
+
+	t.Run("LogDet", func(t *testing.T) {
+		if got := (Matrix{{0, 2}, {3, 1}}).LogDet(); math.Abs(got-math.Log(6)) > 1e-12 {
+			t.Errorf("Expected log 6, got %f", got)
+		}
+		if got := (Matrix{{1, 2}, {2, 4}}).LogDet(); !math.IsInf(got, -1) {
+			t.Errorf("Expected -Inf for a singular matrix, got %f", got)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			a, b     Matrix
+			expected error
+		}{
+			{"Singular", Matrix{{1, 2}, {2, 4}}, Matrix{{1}, {1}}, ErrSingularMatrix},
+			{"Zero", Matrix{{0}}, Matrix{{1}}, ErrSingularMatrix},
+			{"Not square", Matrix{{1, 2}}, Matrix{{1}}, ErrDimensionMismatch},
+			{"Wrong rows", Matrix{{1, 0}, {0, 1}}, Matrix{{1}}, ErrDimensionMismatch},
+		}
+		for _, tt := range tests {
+			if _, err := tt.a.Solve(tt.b); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+			}
+		}
+	})
+}
+
+func TestSolveInPlace(t *testing.T) {
+	t.Run("Leading block", func(t *testing.T) {
+		// Only the leading 2x2 block of a larger scratch matrix is used
+		a := Matrix{{0, 2, 9}, {1, 1, 9}, {9, 9, 9}}
+		b := []float64{4, 3}
+		if err := SolveInPlace(a, b); err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(b[0]-1) > 1e-12 || math.Abs(b[1]-2) > 1e-12 {
+			t.Errorf("Expected [1 2], got %v", b)
+		}
+	})
+
+	t.Run("Allocations", func(t *testing.T) {
+		a := New(3, 3)
+		b := make([]float64, 3)
+		allocs := testing.AllocsPerRun(100, func() {
+			for i := range a {
+				for j := range a[i] {
+					a[i][j] = float64(i*j) + 1
+				}
+				a[i][i] += 2
+				b[i] = 1
+			}
+			SolveInPlace(a, b)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected no allocations, got %f", allocs)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if err := SolveInPlace(Matrix{{1, 2}, {2, 4}}, []float64{1, 1}); !errors.Is(err, ErrSingularMatrix) {
+			t.Errorf("Expected ErrSingularMatrix, got %v", err)
+		}
+		if err := SolveInPlace(Matrix{{1, 2}}, []float64{1, 1}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+}
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Matrix
+		expected int
+	}{
+		{"Full", Matrix{{1, 0}, {0, 1}}, 2},
+		{"Dependent columns", Matrix{{1, 2}, {2, 4}}, 1},
+		{"Zero", New(2, 2), 0},
+		{"Wide", Matrix{{1, 0, 1}, {0, 1, 1}}, 2},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Rank(); got != tt.expected {
+			t.Errorf("%s: expected rank %d, got %d", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -9,41 +9,56 @@ import (
 
 // SimulatedSystem represents a simple mass system for demonstration
 type SimulatedSystem struct {
-	mass     float64 // System mass (kg)
-	position float64 // Current position (m)
-	velocity float64 // Current velocity (m/s)
-	damping  float64 // Damping coefficient
+	mass    float64              // System mass (kg)
+	damping float64              // Damping coefficient
+	model   *feedback.StateSpace // Discrete-time plant model
+	state   feedback.Values      // Current [position, velocity]
 }
 
-// NewSimulatedSystem creates a new simulated system
-func NewSimulatedSystem(mass, damping float64) *SimulatedSystem {
-	return &SimulatedSystem{
-		mass:     mass,
-		position: 0.0,
-		velocity: 0.0,
-		damping:  damping,
+// NewSimulatedSystem creates a new simulated system sampled every dt seconds
+func NewSimulatedSystem(mass, damping, dt float64) (*SimulatedSystem, error) {
+	// F = ma with damping: d/dt [x, v] = [v, (F - damping*v) / mass]
+	plant, err := feedback.NewStateSpace(
+		feedback.Matrix{{0, 1}, {0, -damping / mass}},
+		feedback.Matrix{{0}, {1 / mass}},
+		nil, nil,
+	)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// Update simulates the system dynamics given a control force
-func (sys *SimulatedSystem) Update(force, dt float64) {
-	// Simple physics: F = ma, with damping
-	acceleration := (force - sys.damping*sys.velocity) / sys.mass
+	// Zero-order hold matches a controller that holds the force constant between samples
+	model, err := plant.Discretize(dt, feedback.ZeroOrderHold)
+	if err != nil {
+		return nil, err
+	}
 
-	// Euler integration
-	sys.velocity += acceleration * dt
-	sys.position += sys.velocity * dt
+	return &SimulatedSystem{
+		mass:    mass,
+		damping: damping,
+		model:   model,
+		state:   feedback.Values{0.0, 0.0},
+	}, nil
+}
+
+// Update advances the system dynamics by one sample given a control force
+func (sys *SimulatedSystem) Update(force float64) error {
+	next, err := sys.model.Step(sys.state, feedback.Values{force})
+	if err != nil {
+		return err
+	}
+	sys.state = next
+	return nil
 }
 
 // GetState returns the current system state
 func (sys *SimulatedSystem) GetState() feedback.Values {
-	return feedback.Values{sys.position, sys.velocity}
+	return feedback.Values{sys.state[0], sys.state[1]}
 }
 
 // SetState sets the system state (for initialization)
 func (sys *SimulatedSystem) SetState(position, velocity float64) {
-	sys.position = position
-	sys.velocity = velocity
+	sys.state = feedback.Values{position, velocity}
 }
 
 func main() {
@@ -86,13 +101,17 @@ func main() {
 	gains := feedback.Values{50.0, 20.0}
 	controller := feedback.New(gains)
 
-	// Create simulated system (1 kg mass, 2.0 N·s/m damping)
-	system := NewSimulatedSystem(1.0, 2.0)
-
 	// Simulation parameters
 	dt := 0.01                        // 10ms timestep
 	simulationTime := totalTime + 1.0 // Run a bit longer to see settling
 
+	// Create simulated system (1 kg mass, 2.0 N·s/m damping)
+	system, err := NewSimulatedSystem(1.0, 2.0, dt)
+	if err != nil {
+		fmt.Printf("Model error: %v\n", err)
+		return
+	}
+
 	fmt.Printf("Full-State Feedback Control Simulation:\n")
 	fmt.Printf("Controller Gains: Kp=%.0f N/m, Kd=%.0f N·s/m\n", gains[0], gains[1])
 	fmt.Printf("System: Mass=%.1f kg, Damping=%.1f N·s/m\n", system.mass, system.damping)
@@ -125,7 +144,10 @@ func main() {
 		}
 
 		// Update system dynamics
-		system.Update(force, dt)
+		if err := system.Update(force); err != nil {
+			fmt.Printf("Simulation error: %v\n", err)
+			break
+		}
 
 		// Calculate tracking error
 		positionError := refState.Position - measurement[0]
@@ -161,7 +183,7 @@ func main() {
 	}
 
 	// Calculate final settling error
-	finalError := math.Abs(goal.Position - system.position)
+	finalError := math.Abs(goal.Position - system.GetState()[0])
 
 	// Calculate RMS error over motion profile time
 	rmsError := 0.0
@@ -0,0 +1,16 @@
+package mpc
+
+import (
+	"errors"
+
+	"control/feedback"
+)
+
+var (
+	ErrDimensionMismatch = feedback.ErrDimensionMismatch
+	ErrNotDiscrete       = feedback.ErrNotDiscrete
+	ErrInvalidLimits     = feedback.ErrInvalidLimits
+	ErrNoSolution        = feedback.ErrNoSolution
+	ErrInvalidHorizon    = errors.New("horizon must be positive")
+	ErrInvalidSettings   = errors.New("solver settings must be positive")
+)
@@ -0,0 +1,75 @@
+// Package main demonstrates model predictive control of a gantry axis with travel, speed and
+// force limits.
+//
+// The axis is a 20 kg carriage with viscous friction. MPC plans the force over the next two
+// seconds so that the carriage reaches the target without exceeding its speed limit, where LQR
+// could only clip the force and overshoot the limit.
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"control/feedback"
+	"control/linalg"
+	"control/mpc"
+)
+
+func main() {
+	const (
+		mass     = 20.0  // kg
+		friction = 5.0   // N s/m
+		dt       = 0.01  // 100 Hz control loop
+		maxForce = 100.0 // N
+		maxSpeed = 0.5   // m/s
+		travel   = 1.2   // m
+	)
+
+	fmt.Println("Gantry MPC Example")
+	fmt.Println("==================")
+
+	// d/dt [position, velocity] = [velocity, (force - friction*velocity) / mass]
+	plant, err := feedback.NewStateSpace(
+		feedback.Matrix{{0, 1}, {0, -friction / mass}},
+		feedback.Matrix{{0}, {1 / mass}},
+		nil, nil,
+	)
+	if err != nil {
+		panic(err)
+	}
+	model, err := plant.Discretize(dt, feedback.ZeroOrderHold)
+	if err != nil {
+		panic(err)
+	}
+
+	// A two second horizon sampled at the loop rate
+	controller, err := mpc.New(model, 200, linalg.Diagonal(100, 1), feedback.Matrix{{1e-4}},
+		mpc.WithInputLimits(feedback.Values{-maxForce}, feedback.Values{maxForce}),
+		mpc.WithStateLimits(feedback.Values{0, -maxSpeed}, feedback.Values{travel, maxSpeed}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	x := feedback.Values{0, 0}
+	setpoint := feedback.Values{1, 0}
+	force := make(feedback.Values, 1)
+	peakSpeed := 0.0
+
+	fmt.Printf("%-8s %-10s %-10s %-10s %-6s\n", "Time", "Position", "Velocity", "Force", "Iters")
+	for k := 0; k <= 500; k++ {
+		if err := controller.CalculateInto(force, setpoint, x); err != nil {
+			panic(err)
+		}
+		if k%25 == 0 {
+			fmt.Printf("%-8.2f %-10.4f %-10.4f %-10.2f %-6d\n", float64(k)*dt, x[0], x[1], force[0], controller.Iterations())
+		}
+		if x, err = model.Step(x, force); err != nil {
+			panic(err)
+		}
+		peakSpeed = math.Max(peakSpeed, math.Abs(x[1]))
+	}
+
+	fmt.Printf("\nFinal position: %.4f m (target %.1f m)\n", x[0], setpoint[0])
+	fmt.Printf("Peak speed: %.4f m/s (limit %.1f m/s)\n", peakSpeed, maxSpeed)
+}
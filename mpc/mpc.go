@@ -0,0 +1,399 @@
+package mpc
+
+import (
+	"math"
+	"slices"
+
+	"control/feedback"
+	"control/linalg"
+)
+
+// Option configures an MPC controller.
+type Option func(*config)
+
+// config holds the settings collected from the options before they are validated by New.
+type config struct {
+	inputMin, inputMax feedback.Values
+	stateMin, stateMax feedback.Values
+	terminal           feedback.Matrix
+	rho                float64
+	maxIterations      int
+	absTolerance       float64
+	relTolerance       float64
+}
+
+// WithInputLimits sets box constraints on each input, such as the voltage or force an actuator
+// can deliver. Use math.Inf for inputs that are unbounded on one or both sides.
+func WithInputLimits(min, max feedback.Values) Option {
+	return func(c *config) {
+		c.inputMin = slices.Clone(min)
+		c.inputMax = slices.Clone(max)
+	}
+}
+
+// WithStateLimits sets box constraints on each state over the prediction horizon, such as the
+// travel or speed limits of an axis. Use math.Inf for states that are unbounded on one or both
+// sides. State limits are soft in practice: if they cannot be met from the current state, the
+// solver returns the input that violates them least within its iteration limit. A predicted
+// state that no planned input affects, such as the position one sample ahead of a ForwardEuler
+// model, is not constrained, since no plan could change it.
+func WithStateLimits(min, max feedback.Values) Option {
+	return func(c *config) {
+		c.stateMin = slices.Clone(min)
+		c.stateMax = slices.Clone(max)
+	}
+}
+
+// WithTerminalWeight sets the weight P on the deviation of the last predicted state. By default
+// P is the solution of the discrete algebraic Riccati equation, so that without active
+// constraints the controller matches the infinite-horizon LQR. Set it explicitly for models
+// whose Riccati equation has no solution, such as ones that are not stabilizable.
+func WithTerminalWeight(p feedback.Matrix) Option {
+	return func(c *config) {
+		// Rows are copied one by one so that New can still reject a ragged matrix
+		c.terminal = make(feedback.Matrix, len(p))
+		for i, row := range p {
+			c.terminal[i] = slices.Clone(row)
+		}
+	}
+}
+
+// WithMaxIterations sets the maximum number of solver iterations per call to Calculate. The
+// default is 200.
+func WithMaxIterations(iterations int) Option {
+	return func(c *config) {
+		c.maxIterations = iterations
+	}
+}
+
+// WithTolerance sets the absolute and relative tolerances on the solver residuals. The default
+// is 1e-4 for both.
+func WithTolerance(abs, rel float64) Option {
+	return func(c *config) {
+		c.absTolerance = abs
+		c.relTolerance = rel
+	}
+}
+
+// WithPenalty sets the ADMM penalty parameter rho. Larger values enforce the constraints faster
+// at the cost of slower convergence of the cost. The default is 0.1.
+func WithPenalty(rho float64) Option {
+	return func(c *config) {
+		c.rho = rho
+	}
+}
+
+// MPC is a linear model predictive controller for a discrete-time StateSpace model.
+//
+// Every call to Calculate plans the inputs u[0] to u[N-1] over a horizon of N samples that
+// minimize
+//
+//	sum_{k=1}^{N-1} (x[k] - r)^T Q (x[k] - r) + (x[N] - r)^T P (x[N] - r) + sum_{k=0}^{N-1} u[k]^T R u[k]
+//
+// subject to the input and state limits, and returns u[0]. The quadratic program is solved with
+// ADMM, warm started from the plan of the previous call shifted by one sample, so that a few
+// iterations usually suffice in a running loop. Because R penalizes the input itself, setpoints
+// that need a steady non-zero input are tracked with an offset.
+type MPC struct {
+	states   int
+	inputs   int
+	horizon  int
+	inputMin feedback.Values
+	inputMax feedback.Values
+	stateMin feedback.Values
+	stateMax feedback.Values
+
+	free      linalg.Matrix // Stacked A^k for k = 1..N, the free response to the initial state
+	forced    linalg.Matrix // Stacked responses to the planned inputs
+	gradient  linalg.Matrix // Gamma^T Qbar, mapping the free response error to the cost gradient
+	boxInputs []int         // Inputs with at least one finite limit
+	stateRows []int         // Predicted states k*n+i with a finite limit that the inputs affect
+	solver    *qpSolver
+
+	initial []float64 // Measurement of the last call to Calculate
+
+	// Scratch buffers
+	response     []float64 // Free response, then its deviation from the setpoint
+	q            []float64
+	lower, upper []float64
+}
+
+// New creates a model predictive controller for a discrete-time model with the given horizon in
+// samples, state weight Q and input weight R. Q must be symmetric positive semi-definite and R
+// symmetric positive definite.
+//
+// Returns ErrNotDiscrete if the model is continuous-time, ErrInvalidHorizon if the horizon is not
+// positive, ErrDimensionMismatch if a weight or limit has the wrong size, ErrInvalidLimits if a
+// minimum exceeds its maximum, ErrInvalidSettings if a solver setting is not positive,
+// ErrSingularMatrix if the weights do not define a convex program and, when no terminal weight
+// is given, ErrNoSolution or any other error from feedback.SolveDARE.
+func New(model *feedback.StateSpace, horizon int, q, r feedback.Matrix, opts ...Option) (*MPC, error) {
+	if !model.IsDiscrete() {
+		return nil, ErrNotDiscrete
+	}
+	if horizon <= 0 {
+		return nil, ErrInvalidHorizon
+	}
+	a, b, _, _ := model.Matrices()
+	n, m := model.States(), model.Inputs()
+	if rows, cols := q.Dims(); rows != n || cols != n {
+		return nil, ErrDimensionMismatch
+	}
+	if rows, cols := r.Dims(); rows != m || cols != m {
+		return nil, ErrDimensionMismatch
+	}
+
+	cfg := config{
+		inputMin:      unbounded(m, -1),
+		inputMax:      unbounded(m, 1),
+		stateMin:      unbounded(n, -1),
+		stateMax:      unbounded(n, 1),
+		rho:           0.1,
+		maxIterations: 200,
+		absTolerance:  1e-4,
+		relTolerance:  1e-4,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := validateLimits(cfg.inputMin, cfg.inputMax, m); err != nil {
+		return nil, err
+	}
+	if err := validateLimits(cfg.stateMin, cfg.stateMax, n); err != nil {
+		return nil, err
+	}
+	if cfg.rho <= 0 || cfg.maxIterations <= 0 || cfg.absTolerance <= 0 || cfg.relTolerance <= 0 {
+		return nil, ErrInvalidSettings
+	}
+	terminal := cfg.terminal
+	if terminal == nil {
+		var err error
+		if terminal, err = feedback.SolveDARE(a, b, q, r); err != nil {
+			return nil, err
+		}
+	}
+	if rows, cols := terminal.Dims(); rows != n || cols != n {
+		return nil, ErrDimensionMismatch
+	}
+
+	// x[k+1] = A^(k+1) x[0] + sum_{j<=k} A^(k-j) B u[j]
+	free := linalg.New(horizon*n, n)
+	forced := linalg.New(horizon*n, horizon*m)
+	power := a.Clone()
+	impulse := b.Clone() // A^k B
+	for k := range horizon {
+		for i := range n {
+			copy(free[k*n+i], power[i])
+		}
+		for j := 0; j+k < horizon; j++ {
+			for i := range n {
+				copy(forced[(j+k)*n+i][j*m:(j+1)*m], impulse[i])
+			}
+		}
+		power = a.Mul(power)
+		impulse = a.Mul(impulse)
+	}
+
+	// The cost is 1/2 U^T H U + U^T Gamma^T Qbar (free response - setpoint) plus a constant, with
+	// H = Gamma^T Qbar Gamma + Rbar
+	weights := linalg.New(horizon*n, horizon*n)
+	for k := range horizon {
+		block := q
+		if k == horizon-1 {
+			block = terminal
+		}
+		for i := range n {
+			copy(weights[k*n+i][k*n:(k+1)*n], block[i])
+		}
+	}
+	gradient := forced.Transpose().Mul(weights)
+	hessian := gradient.Mul(forced)
+	for k := range horizon {
+		for i := range m {
+			for j := range m {
+				hessian[k*m+i][k*m+j] += r[i][j]
+			}
+		}
+	}
+
+	// The input limits of every stage come first, followed by the state limits. A state that no
+	// planned input affects, such as a position one sample ahead under ForwardEuler, would give a
+	// zero row that no plan can satisfy if the free response breaks its limit, so it is left out.
+	boxInputs := bounded(cfg.inputMin, cfg.inputMax)
+	boxStates := bounded(cfg.stateMin, cfg.stateMax)
+	var stateRows []int
+	for k := range horizon {
+		for _, i := range boxStates {
+			if slices.ContainsFunc(forced[k*n+i], func(v float64) bool { return v != 0 }) {
+				stateRows = append(stateRows, k*n+i)
+			}
+		}
+	}
+	inputRows := horizon * len(boxInputs)
+	constraints := linalg.New(inputRows+len(stateRows), horizon*m)
+	for k := range horizon {
+		for j, i := range boxInputs {
+			constraints[k*len(boxInputs)+j][k*m+i] = 1
+		}
+	}
+	for j, index := range stateRows {
+		copy(constraints[inputRows+j], forced[index])
+	}
+
+	solver, err := newQPSolver(hessian.Symmetrize(), constraints, cfg.rho, cfg.maxIterations, cfg.absTolerance, cfg.relTolerance)
+	if err != nil {
+		return nil, err
+	}
+	return &MPC{
+		states:    n,
+		inputs:    m,
+		horizon:   horizon,
+		inputMin:  cfg.inputMin,
+		inputMax:  cfg.inputMax,
+		stateMin:  cfg.stateMin,
+		stateMax:  cfg.stateMax,
+		free:      free,
+		forced:    forced,
+		gradient:  gradient,
+		boxInputs: boxInputs,
+		stateRows: stateRows,
+		solver:    solver,
+		initial:   make([]float64, n),
+		response:  make([]float64, horizon*n),
+		q:         make([]float64, horizon*m),
+		lower:     make([]float64, len(constraints)),
+		upper:     make([]float64, len(constraints)),
+	}, nil
+}
+
+// Calculate plans the inputs that drive the measured state towards the setpoint and returns the
+// first of them, clamped to the input limits.
+//
+// Returns ErrDimensionMismatch if the setpoint or measurement does not have one value per state.
+func (c *MPC) Calculate(setpoint, measurement feedback.Values) (feedback.Values, error) {
+	output := make(feedback.Values, c.inputs)
+	if err := c.CalculateInto(output, setpoint, measurement); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// CalculateInto is like Calculate, but writes the input into a caller-owned buffer with one
+// value per input and does not allocate.
+//
+// Returns ErrDimensionMismatch if a vector has the wrong length.
+func (c *MPC) CalculateInto(output, setpoint, measurement feedback.Values) error {
+	if len(setpoint) != c.states || len(measurement) != c.states || len(output) != c.inputs {
+		return ErrDimensionMismatch
+	}
+
+	copy(c.initial, measurement)
+
+	// State limits apply to the forced response, so shift them by the free response
+	c.free.MulVec(c.response, measurement)
+	row := 0
+	for range c.horizon {
+		for _, i := range c.boxInputs {
+			c.lower[row], c.upper[row] = c.inputMin[i], c.inputMax[i]
+			row++
+		}
+	}
+	for _, index := range c.stateRows {
+		i, free := index%c.states, c.response[index]
+		c.lower[row], c.upper[row] = c.stateMin[i]-free, c.stateMax[i]-free
+		row++
+	}
+	for k := range c.horizon {
+		for i, r := range setpoint {
+			c.response[k*c.states+i] -= r
+		}
+	}
+	c.gradient.MulVec(c.q, c.response)
+
+	// Warm start from the previous plan, advanced by one sample
+	if c.horizon > 1 {
+		c.solver.shift(c.inputs)
+	}
+	c.solver.solve(c.q, c.lower, c.upper)
+
+	for i := range output {
+		output[i] = math.Min(math.Max(c.solver.x[i], c.inputMin[i]), c.inputMax[i])
+	}
+	return nil
+}
+
+// GetPlan returns the inputs u[0] to u[N-1] and the predicted states x[1] to x[N] planned by the
+// last call to Calculate. The inputs are not clamped, so they may exceed the limits by up to the
+// solver tolerance.
+func (c *MPC) GetPlan() (inputs, states []feedback.Values) {
+	predicted := make([]float64, c.horizon*c.states)
+	c.free.MulVec(predicted, c.initial)
+	for i, row := range c.forced {
+		predicted[i] += linalg.Dot(row, c.solver.x)
+	}
+
+	inputs = make([]feedback.Values, c.horizon)
+	states = make([]feedback.Values, c.horizon)
+	for k := range c.horizon {
+		inputs[k] = slices.Clone(c.solver.x[k*c.inputs : (k+1)*c.inputs])
+		states[k] = predicted[k*c.states : (k+1)*c.states : (k+1)*c.states]
+	}
+	return inputs, states
+}
+
+// Iterations returns the number of solver iterations used by the last call to Calculate.
+func (c *MPC) Iterations() int {
+	return c.solver.iterations
+}
+
+// Converged reports whether the solver met its tolerances in the last call to Calculate. If it
+// did not, the returned input is the best found within the iteration limit and still respects
+// the input limits. State limits that cannot be met, such as a position limit approached too fast
+// to stop, also end here; the next call then rebuilds the solver's multipliers from zero, so the
+// controller recovers once the limits can be met again.
+func (c *MPC) Converged() bool {
+	return c.solver.converged
+}
+
+// GetHorizon returns the prediction horizon in samples.
+func (c *MPC) GetHorizon() int {
+	return c.horizon
+}
+
+// Reset discards the previous plan, so that the next call to Calculate starts the solver cold.
+func (c *MPC) Reset() {
+	c.solver.reset()
+}
+
+// unbounded returns a vector of n infinities with the given sign.
+func unbounded(n, sign int) feedback.Values {
+	result := make(feedback.Values, n)
+	for i := range result {
+		result[i] = math.Inf(sign)
+	}
+	return result
+}
+
+// validateLimits checks that the limits have n values each and no minimum exceeds its maximum.
+func validateLimits(min, max feedback.Values, n int) error {
+	if len(min) != n || len(max) != n {
+		return ErrDimensionMismatch
+	}
+	for i := range min {
+		if math.IsNaN(min[i]) || math.IsNaN(max[i]) || min[i] > max[i] {
+			return ErrInvalidLimits
+		}
+	}
+	return nil
+}
+
+// bounded returns the indices with at least one finite limit.
+func bounded(min, max feedback.Values) []int {
+	var result []int
+	for i := range min {
+		if !math.IsInf(min[i], -1) || !math.IsInf(max[i], 1) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
@@ -0,0 +1,274 @@
+package mpc
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"control/feedback"
+	"control/linalg"
+)
+
+// doubleIntegrator returns a unit mass driven by a force, sampled every 0.1 s, with states
+// position and velocity.
+func doubleIntegrator(t testing.TB) *feedback.StateSpace {
+	t.Helper()
+	const dt = 0.1
+	model, err := feedback.NewDiscreteStateSpace(
+		feedback.Matrix{{1, dt}, {0, 1}},
+		feedback.Matrix{{0.5 * dt * dt}, {dt}},
+		nil, nil, dt,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+// simulate runs the closed loop from x0 towards the setpoint and returns the visited states and
+// applied inputs.
+func simulate(t *testing.T, controller *MPC, model *feedback.StateSpace, x0, setpoint feedback.Values, steps int) (states, inputs []feedback.Values) {
+	t.Helper()
+	x := x0
+	for range steps {
+		u, err := controller.Calculate(setpoint, x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x, err = model.Step(x, u); err != nil {
+			t.Fatal(err)
+		}
+		states = append(states, x)
+		inputs = append(inputs, u)
+	}
+	return states, inputs
+}
+
+func TestMPC(t *testing.T) {
+	model := doubleIntegrator(t)
+	a, b, _, _ := model.Matrices()
+	q := linalg.Identity(2)
+	r := feedback.Matrix{{1}}
+
+	t.Run("Unconstrained matches LQR", func(t *testing.T) {
+		k, err := feedback.DiscreteLQR(a, b, q, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		controller, err := New(model, 10, q, r, WithTolerance(1e-9, 1e-9))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, x := range []feedback.Values{{1, 0}, {-2, 0.5}, {0.3, -4}} {
+			u, err := controller.Calculate(feedback.Values{0, 0}, x)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := -(k[0][0]*x[0] + k[0][1]*x[1])
+			if math.Abs(u[0]-expected) > 1e-6 {
+				t.Errorf("x = %v: u = %f, expected the LQR input %f", x, u[0], expected)
+			}
+		}
+	})
+
+	t.Run("Input limits", func(t *testing.T) {
+		controller, err := New(model, 20, q, r, WithInputLimits(feedback.Values{-1}, feedback.Values{1}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		states, inputs := simulate(t, controller, model, feedback.Values{10, 0}, feedback.Values{0, 0}, 300)
+		if inputs[0][0] != -1 {
+			t.Errorf("Expected the first input to saturate at -1, got %f", inputs[0][0])
+		}
+		for k, u := range inputs {
+			if math.Abs(u[0]) > 1 {
+				t.Fatalf("Input %d = %f exceeds the limit", k, u[0])
+			}
+		}
+		if last := states[len(states)-1]; math.Abs(last[0]) > 1e-2 || math.Abs(last[1]) > 1e-2 {
+			t.Errorf("Expected the state to converge, got %v", last)
+		}
+	})
+
+	t.Run("State limits", func(t *testing.T) {
+		const speed = 1.5
+		controller, err := New(model, 20, q, r,
+			WithInputLimits(feedback.Values{-2}, feedback.Values{2}),
+			WithStateLimits(feedback.Values{math.Inf(-1), -speed}, feedback.Values{math.Inf(1), speed}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		states, _ := simulate(t, controller, model, feedback.Values{10, 0}, feedback.Values{0, 0}, 300)
+		for k, x := range states {
+			if math.Abs(x[1]) > speed+1e-2 {
+				t.Fatalf("Velocity %f at step %d exceeds the limit", x[1], k)
+			}
+		}
+		if last := states[len(states)-1]; math.Abs(last[0]) > 1e-2 {
+			t.Errorf("Expected the position to converge, got %v", last)
+		}
+
+		// The plan respects the limits over the whole horizon
+		plannedInputs, plannedStates := controller.GetPlan()
+		if len(plannedInputs) != 20 || len(plannedStates) != 20 {
+			t.Fatalf("Expected a plan of 20 samples, got %d and %d", len(plannedInputs), len(plannedStates))
+		}
+	})
+
+	t.Run("State limit the inputs cannot reach", func(t *testing.T) {
+		// Under forward Euler the next position does not depend on the input, so a limit it
+		// already breaks one step ahead must not stall the solver
+		continuous, _ := feedback.NewStateSpace(feedback.Matrix{{0, 1}, {0, 0}}, feedback.Matrix{{0}, {1}}, nil, nil)
+		euler, err := continuous.Discretize(0.1, feedback.ForwardEuler)
+		if err != nil {
+			t.Fatal(err)
+		}
+		controller, err := New(euler, 20, q, r,
+			WithInputLimits(feedback.Values{-1}, feedback.Values{1}),
+			WithStateLimits(feedback.Values{math.Inf(-1), math.Inf(-1)}, feedback.Values{0.9, math.Inf(1)}),
+			WithMaxIterations(1000),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := controller.Calculate(feedback.Values{0.5, 0}, feedback.Values{1, -0.8}); err != nil {
+			t.Fatal(err)
+		}
+		if !controller.Converged() {
+			t.Errorf("Expected the solver to converge, stopped after %d iterations", controller.Iterations())
+		}
+	})
+
+	t.Run("Setpoint", func(t *testing.T) {
+		controller, err := New(model, 20, q, r, WithInputLimits(feedback.Values{-1}, feedback.Values{1}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		states, _ := simulate(t, controller, model, feedback.Values{0, 0}, feedback.Values{3, 0}, 300)
+		if last := states[len(states)-1]; math.Abs(last[0]-3) > 1e-2 {
+			t.Errorf("Expected the position to reach 3, got %v", last)
+		}
+	})
+
+	t.Run("Warm start", func(t *testing.T) {
+		opts := []Option{
+			WithInputLimits(feedback.Values{-1}, feedback.Values{1}),
+			WithStateLimits(feedback.Values{math.Inf(-1), -1.5}, feedback.Values{math.Inf(1), 1.5}),
+		}
+		warm, _ := New(model, 20, q, r, opts...)
+		cold, _ := New(model, 20, q, r, opts...)
+
+		x := feedback.Values{10, 0}
+		var warmIterations, coldIterations int
+		for range 50 {
+			u, _ := warm.Calculate(feedback.Values{0, 0}, x)
+			warmIterations += warm.Iterations()
+			cold.Reset()
+			cold.Calculate(feedback.Values{0, 0}, x)
+			coldIterations += cold.Iterations()
+			x, _ = model.Step(x, u)
+		}
+		if warmIterations >= coldIterations {
+			t.Errorf("Expected warm starting to save iterations, got %d warm and %d cold", warmIterations, coldIterations)
+		}
+	})
+
+	t.Run("Recovery after infeasibility", func(t *testing.T) {
+		// Too fast to stop before the position limit, so the first problems are infeasible
+		controller, err := New(model, 20, q, r,
+			WithInputLimits(feedback.Values{-1}, feedback.Values{1}),
+			WithStateLimits(feedback.Values{math.Inf(-1), math.Inf(-1)}, feedback.Values{0.9, math.Inf(1)}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		states, _ := simulate(t, controller, model, feedback.Values{0.85, 2}, feedback.Values{0.5, 0}, 150)
+		if !controller.Converged() {
+			t.Errorf("Expected the solver to converge again, stopped after %d iterations", controller.Iterations())
+		}
+		if last := states[len(states)-1]; math.Abs(last[0]-0.5) > 1e-2 || math.Abs(last[1]) > 1e-2 {
+			t.Errorf("Expected the state to settle at the setpoint, got %v", last)
+		}
+	})
+
+	t.Run("Allocations", func(t *testing.T) {
+		controller, _ := New(model, 20, q, r, WithInputLimits(feedback.Values{-1}, feedback.Values{1}))
+		output := make(feedback.Values, 1)
+		setpoint, x := feedback.Values{0, 0}, feedback.Values{10, 0}
+		allocs := testing.AllocsPerRun(100, func() {
+			controller.CalculateInto(output, setpoint, x)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected no allocations, got %f", allocs)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		continuous, _ := feedback.NewStateSpace(feedback.Matrix{{0}}, feedback.Matrix{{1}}, nil, nil)
+		limits := feedback.Values{-1}
+		tests := []struct {
+			name     string
+			model    *feedback.StateSpace
+			horizon  int
+			q        feedback.Matrix
+			opts     []Option
+			expected error
+		}{
+			{"Continuous model", continuous, 10, linalg.Identity(1), nil, ErrNotDiscrete},
+			{"Zero horizon", model, 0, q, nil, ErrInvalidHorizon},
+			{"Wrong Q size", model, 10, linalg.Identity(3), nil, ErrDimensionMismatch},
+			{"Wrong limit length", model, 10, q, []Option{WithInputLimits(feedback.Values{-1, -1}, feedback.Values{1, 1})}, ErrDimensionMismatch},
+			{"Inverted limits", model, 10, q, []Option{WithInputLimits(feedback.Values{1}, limits)}, ErrInvalidLimits},
+			{"Inverted state limits", model, 10, q, []Option{WithStateLimits(feedback.Values{0, 1}, feedback.Values{0, 0})}, ErrInvalidLimits},
+			{"Wrong terminal size", model, 10, q, []Option{WithTerminalWeight(linalg.Identity(1))}, ErrDimensionMismatch},
+			{"Ragged Q", model, 10, feedback.Matrix{{1, 0}, {0}}, nil, ErrDimensionMismatch},
+			{"Ragged terminal", model, 10, q, []Option{WithTerminalWeight(feedback.Matrix{{1, 0}, {0}})}, ErrDimensionMismatch},
+			{"Zero iterations", model, 10, q, []Option{WithMaxIterations(0)}, ErrInvalidSettings},
+			{"Negative penalty", model, 10, q, []Option{WithPenalty(-1)}, ErrInvalidSettings},
+		}
+		for _, tt := range tests {
+			if _, err := New(tt.model, tt.horizon, tt.q, r, tt.opts...); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+			}
+		}
+
+		if _, err := New(model, 10, q, feedback.Matrix{{1}, {}}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Ragged R: expected ErrDimensionMismatch, got %v", err)
+		}
+
+		// The unstable first state is not driven by the input, so the default terminal weight
+		// does not exist, but an explicit one can still be used
+		unstabilizable, _ := feedback.NewDiscreteStateSpace(
+			feedback.Matrix{{1.1, 0}, {0, 1}}, feedback.Matrix{{0}, {1}}, nil, nil, 0.1)
+		if _, err := New(unstabilizable, 10, q, r); !errors.Is(err, ErrNoSolution) {
+			t.Errorf("Expected ErrNoSolution, got %v", err)
+		}
+		if _, err := New(unstabilizable, 10, q, r, WithTerminalWeight(q)); err != nil {
+			t.Errorf("Expected an explicit terminal weight to be accepted, got %v", err)
+		}
+
+		controller, _ := New(model, 10, q, r)
+		if _, err := controller.Calculate(feedback.Values{0}, feedback.Values{0, 0}); !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+}
+
+func BenchmarkMPCCalculateInto(b *testing.B) {
+	model := doubleIntegrator(b)
+	controller, _ := New(model, 20, linalg.Identity(2), feedback.Matrix{{1}},
+		WithInputLimits(feedback.Values{-1}, feedback.Values{1}),
+		WithStateLimits(feedback.Values{math.Inf(-1), -1.5}, feedback.Values{math.Inf(1), 1.5}),
+	)
+	output := make(feedback.Values, 1)
+	setpoint := feedback.Values{0, 0}
+	x := feedback.Values{10, 0}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		controller.CalculateInto(output, setpoint, x)
+		x[0] += 0.1*x[1] + 0.005*output[0]
+		x[1] += 0.1 * output[0]
+	}
+}
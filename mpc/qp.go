@@ -0,0 +1,196 @@
+package mpc
+
+import (
+	"math"
+
+	"control/linalg"
+)
+
+const (
+	// qpSigma is the small proximal weight that keeps the ADMM linear system positive definite
+	qpSigma = 1e-6
+
+	// qpAlpha is the over-relaxation factor of the ADMM iterations
+	qpAlpha = 1.6
+)
+
+// qpSolver solves quadratic programs
+//
+//	minimize 1/2 x^T H x + q^T x subject to lower <= C x <= upper
+//
+// with the alternating direction method of multipliers (ADMM), using the splitting of the OSQP
+// solver. H and C are fixed while q and the bounds change between solves, so the linear system
+// of every iteration is inverted once when the solver is created and an iteration costs a few
+// matrix-vector products. The iterates are kept between solves to warm start the next one.
+type qpSolver struct {
+	h, c, ct  linalg.Matrix // Scaled problem data
+	kkt       linalg.Matrix // (H + sigma I + rho C^T C)^-1
+	costScale float64       // Scale applied to H and q
+	rowScale  []float64     // Scale applied to each row of C and its bounds
+
+	rho           float64
+	maxIterations int
+	absTolerance  float64
+	relTolerance  float64
+
+	x, z, y []float64 // Primal, constraint and dual iterates
+	xt, zt  []float64 // Unrelaxed iterates
+	rhs     []float64 // Right-hand side of the linear system, also the dual residual
+	work    []float64 // Constraint-sized scratch
+
+	iterations int
+	converged  bool
+}
+
+// newQPSolver creates a solver for the given H and C.
+//
+// Returns ErrSingularMatrix if H + sigma I + rho C^T C cannot be inverted, which happens only
+// when H is far from positive semi-definite.
+func newQPSolver(h, c linalg.Matrix, rho float64, maxIterations int, absTolerance, relTolerance float64) (*qpSolver, error) {
+	variables, _ := h.Dims()
+	constraints := len(c)
+
+	// Scale every constraint row to unit length and the cost to unit size, so that a single
+	// penalty suits rows of very different magnitude such as input and state limits
+	rowScale := make([]float64, constraints)
+	c = c.Clone()
+	for i, row := range c {
+		rowScale[i] = 1 / math.Max(math.Sqrt(linalg.Dot(row, row)), 1e-12)
+		for j := range row {
+			row[j] *= rowScale[i]
+		}
+	}
+	costScale := 1 / math.Max(h.Norm(), 1e-12)
+	h = h.Scale(costScale)
+
+	ct := c.Transpose()
+	if constraints == 0 {
+		ct = linalg.New(variables, 0)
+	}
+	system := h.Add(linalg.Identity(variables).Scale(qpSigma))
+	if constraints > 0 {
+		system = system.Add(ct.Mul(c).Scale(rho))
+	}
+	kkt, err := system.Symmetrize().Inverse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &qpSolver{
+		h:             h,
+		c:             c,
+		ct:            ct,
+		kkt:           kkt,
+		costScale:     costScale,
+		rowScale:      rowScale,
+		rho:           rho,
+		maxIterations: maxIterations,
+		absTolerance:  absTolerance,
+		relTolerance:  relTolerance,
+		x:             make([]float64, variables),
+		z:             make([]float64, constraints),
+		y:             make([]float64, constraints),
+		xt:            make([]float64, variables),
+		zt:            make([]float64, constraints),
+		rhs:           make([]float64, variables),
+		work:          make([]float64, constraints),
+	}, nil
+}
+
+// solve runs ADMM from the current iterates until the residuals meet the tolerances or the
+// iteration limit is reached, leaving the solution in x. The duals are cleared when the limit is
+// reached. The gradient and bounds are scaled in
+// place. It does not allocate.
+func (s *qpSolver) solve(q, lower, upper []float64) {
+	for j := range q {
+		q[j] *= s.costScale
+	}
+	for i, scale := range s.rowScale {
+		lower[i] *= scale
+		upper[i] *= scale
+	}
+
+	// Start from the constraint values of the current solution, which is exact for a plan that
+	// is still feasible
+	s.c.MulVec(s.z, s.x)
+	for i := range s.z {
+		s.z[i] = math.Min(math.Max(s.z[i], lower[i]), upper[i])
+	}
+
+	s.converged = false
+	for s.iterations = 1; s.iterations <= s.maxIterations; s.iterations++ {
+		// (H + sigma I + rho C^T C) xt = sigma x - q + C^T (rho z - y)
+		for i := range s.work {
+			s.work[i] = s.rho*s.z[i] - s.y[i]
+		}
+		s.ct.MulVec(s.rhs, s.work)
+		for j := range s.rhs {
+			s.rhs[j] += qpSigma*s.x[j] - q[j]
+		}
+		s.kkt.MulVec(s.xt, s.rhs)
+		s.c.MulVec(s.zt, s.xt)
+
+		for j := range s.x {
+			s.x[j] = qpAlpha*s.xt[j] + (1-qpAlpha)*s.x[j]
+		}
+		for i := range s.z {
+			relaxed := qpAlpha*s.zt[i] + (1-qpAlpha)*s.z[i]
+			next := math.Min(math.Max(relaxed+s.y[i]/s.rho, lower[i]), upper[i])
+			s.y[i] += s.rho * (relaxed - next)
+			s.z[i] = next
+		}
+
+		if s.residualsConverged(q) {
+			s.converged = true
+			return
+		}
+	}
+	s.iterations = s.maxIterations
+
+	// Duals that did not settle, as on an infeasible problem where they grow without bound, would
+	// carry the failure into the following solves, so the next one rebuilds them from zero
+	clear(s.y)
+}
+
+// residualsConverged reports whether the primal residual C x - z and the dual residual
+// H x + q + C^T y are within the tolerances.
+func (s *qpSolver) residualsConverged(q []float64) bool {
+	// Primal residual
+	s.c.MulVec(s.zt, s.x)
+	var primal, cx, z float64
+	for i := range s.z {
+		primal = math.Max(primal, math.Abs(s.zt[i]-s.z[i]))
+		cx = math.Max(cx, math.Abs(s.zt[i]))
+		z = math.Max(z, math.Abs(s.z[i]))
+	}
+	if primal > s.absTolerance+s.relTolerance*math.Max(cx, z) {
+		return false
+	}
+
+	// Dual residual, using xt and rhs as scratch
+	s.h.MulVec(s.xt, s.x)
+	s.ct.MulVec(s.rhs, s.y)
+	var dual, hx, cty, qNorm float64
+	for j := range s.x {
+		dual = math.Max(dual, math.Abs(s.xt[j]+q[j]+s.rhs[j]))
+		hx = math.Max(hx, math.Abs(s.xt[j]))
+		cty = math.Max(cty, math.Abs(s.rhs[j]))
+		qNorm = math.Max(qNorm, math.Abs(q[j]))
+	}
+	return dual <= s.absTolerance+s.relTolerance*math.Max(hx, math.Max(cty, qNorm))
+}
+
+// shift moves the solution forward by the given number of variables, repeating the last ones,
+// to warm start a problem that continues the previous one in time. The duals are kept in place:
+// they vary along a finite horizon, and are a better starting point unshifted.
+func (s *qpSolver) shift(variables int) {
+	copy(s.x, s.x[variables:])
+	copy(s.x[len(s.x)-variables:], s.x[len(s.x)-2*variables:])
+}
+
+// reset clears the iterates so that the next solve starts cold.
+func (s *qpSolver) reset() {
+	clear(s.x)
+	clear(s.z)
+	clear(s.y)
+}
@@ -0,0 +1,42 @@
+package mpc
+
+import (
+	"math"
+	"testing"
+
+	"control/linalg"
+)
+
+func TestQPSolver(t *testing.T) {
+	// minimize 1/2 (x1^2 + x2^2) - x1 - x2, whose unconstrained minimum is (1, 1)
+	h := linalg.Identity(2)
+	q := []float64{-1, -1}
+
+	tests := []struct {
+		name         string
+		c            linalg.Matrix
+		lower, upper []float64
+		expected     []float64
+	}{
+		{"Unconstrained", nil, nil, nil, []float64{1, 1}},
+		{"Inactive", linalg.Matrix{{1, 1}}, []float64{-10}, []float64{10}, []float64{1, 1}},
+		{"Sum", linalg.Matrix{{1, 1}}, []float64{math.Inf(-1)}, []float64{1}, []float64{0.5, 0.5}},
+		{"Box", linalg.Identity(2), []float64{0, -1}, []float64{0.2, 3}, []float64{0.2, 1}},
+		{"Equality", linalg.Matrix{{1, -1}}, []float64{1}, []float64{1}, []float64{1.5, 0.5}},
+	}
+	for _, tt := range tests {
+		solver, err := newQPSolver(h, tt.c, 0.1, 1000, 1e-8, 1e-8)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		solver.solve(q, tt.lower, tt.upper)
+		if !solver.converged {
+			t.Errorf("%s: did not converge in %d iterations", tt.name, solver.iterations)
+		}
+		for i, v := range tt.expected {
+			if math.Abs(solver.x[i]-v) > 1e-6 {
+				t.Errorf("%s: x[%d] = %f, expected %f", tt.name, i, solver.x[i], v)
+			}
+		}
+	}
+}
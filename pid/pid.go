@@ -18,13 +18,14 @@ type PID struct {
 	kd float64 // Derivative gain
 
 	// Options
-	feedForward              float64       // Feed-forward value added to PID output
-	integralResetOnZeroCross bool          // Reset integral when error crosses zero
-	stabilityThreshold       float64       // Derivative threshold to disable integral calculation
-	integralSumMax           float64       // Maximum absolute value of integral sum
-	outputMin                float64       // Minimum output value
-	outputMax                float64       // Maximum output value
-	filter                   filter.Filter // Filter for derivative term
+	feedForward              float64               // Feed-forward value added to PID output
+	integralResetOnZeroCross bool                  // Reset integral when error crosses zero
+	stabilityThreshold       float64               // Derivative threshold to disable integral calculation
+	integralSumMax           float64               // Maximum absolute value of integral sum
+	outputMin                float64               // Minimum output value
+	outputMax                float64               // Maximum output value
+	filter                   filter.Filter         // Filter for derivative term
+	differentiator           filter.Differentiator // Derivative estimator for the error
 
 	// Internal state
 	integral      float64   // Accumulated integral term
@@ -87,12 +88,22 @@ func WithIntegralSumMax(maxSum float64) Option {
 }
 
 // WithFilter sets a filter for the derivative term. Examples are a low pass filter or a kalman filter.
+// The filter is stepped exactly once per calculation with the change in error since the previous one.
 func WithFilter(f filter.Filter) Option {
 	return func(p *PID) {
 		p.filter = f
 	}
 }
 
+// WithDifferentiator sets a derivative estimator that is used in place of the first difference of the error.
+// Examples are filter.FilteredDerivative, filter.RegressionDerivative, filter.SavitzkyGolayFilter or
+// filter.TrackingDifferentiator. When set, the derivative filter is not applied.
+func WithDifferentiator(d filter.Differentiator) Option {
+	return func(p *PID) {
+		p.differentiator = d
+	}
+}
+
 // WithOutputLimits sets the minimum and maximum output limits
 func WithOutputLimits(min, max float64) Option {
 	return func(p *PID) {
@@ -145,6 +156,10 @@ func (p *PID) Calculate(reference, state float64) float64 {
 		p.lastError = error
 		p.prevTime = now
 		p.initialized = true
+		if p.differentiator != nil {
+			// Record the first sample so the next update has a previous value
+			p.differentiator.Differentiate(error, 0)
+		}
 		return 0
 	}
 
@@ -177,10 +192,12 @@ func (p *PID) CalculateWithDt(reference, state, dt float64) float64 {
 		p.lastReference = reference
 	}
 
-	// Calculate PID terms
+	// Calculate PID terms. The raw derivative is estimated once, as filters and
+	// differentiators are stateful and must see each sample exactly once.
+	rawDerivative := p.calculateRawDerivative(error, dt)
 	proportional := p.calculateProportional(error)
-	derivative := p.calcualteDerrivative(error, dt)
-	integral := p.calculateIntegral(error, dt)
+	derivative := p.calcualteDerrivative(rawDerivative)
+	integral := p.calculateIntegral(error, rawDerivative, dt)
 
 	// Calculate output
 	output := proportional + integral + derivative + p.feedForward
@@ -205,15 +222,14 @@ func (p *PID) calculateProportional(error float64) float64 {
 	return proportional
 }
 
-// calculateIntegral computes the integral term for a given error and time delta
-func (p *PID) calculateIntegral(error, dt float64) float64 {
+// calculateIntegral computes the integral term for a given error, raw derivative and time delta
+func (p *PID) calculateIntegral(error, rawDerivative, dt float64) float64 {
 	// Check for zero crossover and reset integral if enabled
 	if p.integralResetOnZeroCross && ((p.lastError > 0 && error < 0) || (p.lastError < 0 && error > 0)) {
 		p.integral = 0
 	}
 
 	// Integral term with stability threshold check
-	rawDerivative := p.calculateRawDerivative(error, dt)
 	if math.IsNaN(p.stabilityThreshold) || math.Abs(rawDerivative) <= p.stabilityThreshold {
 		p.integral += error * dt
 
@@ -231,9 +247,8 @@ func (p *PID) calculateIntegral(error, dt float64) float64 {
 	return integral
 }
 
-// calcualteDerrivative computes the derivative term for a given error and time delta
-func (p *PID) calcualteDerrivative(error, dt float64) float64 {
-	rawDerivative := p.calculateRawDerivative(error, dt)
+// calcualteDerrivative computes the derivative term for a given raw derivative
+func (p *PID) calcualteDerrivative(rawDerivative float64) float64 {
 	derivative := p.kd * rawDerivative
 
 	return derivative
@@ -241,6 +256,11 @@ func (p *PID) calcualteDerrivative(error, dt float64) float64 {
 
 // calculateRawDerivative computes the raw derivative term for a given error and time delta
 func (p *PID) calculateRawDerivative(error, dt float64) float64 {
+	if p.differentiator != nil {
+		// Differentiators record every sample and return 0 themselves when dt is not positive
+		return p.differentiator.Differentiate(error, dt)
+	}
+
 	if dt <= 0 {
 		return 0
 	}
@@ -278,6 +298,9 @@ func (p *PID) Reset() *PID {
 	if p.filter != nil {
 		p.filter.Reset()
 	}
+	if p.differentiator != nil {
+		p.differentiator.Reset()
+	}
 	return p
 }
 
@@ -355,6 +378,18 @@ func (p *PID) GetFilter() filter.Filter {
 	return p.filter
 }
 
+// SetDifferentiator sets a derivative estimator used in place of the first difference of the error.
+// Passing nil restores the first difference.
+func (p *PID) SetDifferentiator(d filter.Differentiator) *PID {
+	p.differentiator = d
+	return p
+}
+
+// GetDifferentiator returns the current derivative estimator, or nil if the first difference is used.
+func (p *PID) GetDifferentiator() filter.Differentiator {
+	return p.differentiator
+}
+
 // SetOutputLimits sets the minimum and maximum output values
 func (p *PID) SetOutputLimits(min, max float64) *PID {
 	if min > max {
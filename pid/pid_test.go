@@ -169,6 +169,29 @@ func TestWithFilter(t *testing.T) {
 			t.Error("Expected nil filter")
 		}
 	})
+
+	t.Run("With filter chain", func(t *testing.T) {
+		median, _ := filter.NewMedianFilter(5)
+		lowPass, _ := filter.NewLowPassFilter(0.5)
+		chain := filter.Chain(median, lowPass)
+		pid := New(0.0, 0.0, 1.0, WithFilter(chain))
+
+		// A single-sample spike in the error change is removed by the median stage
+		outputs := make([]float64, 0, 6)
+		for _, state := range []float64{0.0, 0.0, 0.0, 10.0, 0.0, 0.0} {
+			outputs = append(outputs, pid.CalculateWithDt(0.0, state, 0.1))
+		}
+		for i, output := range outputs {
+			if math.Abs(output) > 1e-9 {
+				t.Errorf("Step %d: expected derivative spike to be rejected, got %f", i, output)
+			}
+		}
+
+		pid.Reset()
+		if lowPass.IsInitialized() {
+			t.Error("Expected Reset to reset every stage of the chain")
+		}
+	})
 }
 
 func TestWithOutputLimits(t *testing.T) {
@@ -426,6 +449,154 @@ func TestDerivativeFilter(t *testing.T) {
 	}
 }
 
+// countingFilter is a stateful pass-through filter that records how often it is stepped
+type countingFilter struct {
+	steps int
+}
+
+func (f *countingFilter) Estimate(measurement float64) float64 {
+	f.steps++
+	return measurement
+}
+
+func (f *countingFilter) Reset() {
+	f.steps = 0
+}
+
+func (f *countingFilter) GetGain() float64 {
+	return 1.0
+}
+
+func TestDerivativeFilterStepsOncePerSample(t *testing.T) {
+	counter := &countingFilter{}
+	pid := New(1.0, 0.1, 0.05, WithFilter(counter))
+
+	const samples = 20
+	for i := 0; i < samples; i++ {
+		pid.CalculateWithDt(1.0, float64(i)*0.01, 0.01)
+	}
+
+	if counter.steps != samples {
+		t.Errorf("Expected the derivative filter to be stepped %d times, got %d", samples, counter.steps)
+	}
+
+	// A stateful low pass filter must produce the same derivative as one stepped by hand
+	lowPass, _ := filter.NewLowPassFilter(0.5)
+	reference, _ := filter.NewLowPassFilter(0.5)
+	pid = New(0.0, 0.0, 1.0, WithFilter(lowPass))
+	// The first sample initializes the controller, so its change in error is zero
+	lastError := math.Sin(0.5)
+	for i := 0; i < samples; i++ {
+		error := math.Sin(float64(i)*0.3 + 0.5)
+		output := pid.CalculateWithDt(error, 0.0, 0.01)
+		expected := reference.Estimate(error-lastError) / 0.01
+		lastError = error
+		if !almostEqual(output, expected, 1e-9) {
+			t.Fatalf("Sample %d: expected filtered derivative %f, got %f", i, expected, output)
+		}
+	}
+}
+
+func TestWithDifferentiator(t *testing.T) {
+	t.Run("Savitzky-Golay on quantized error", func(t *testing.T) {
+		dt := 0.005
+		sg, err := filter.NewSavitzkyGolayFilter(25, 2, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		smooth := New(0.0, 0.0, 1.0, WithDifferentiator(sg))
+		raw := New(0.0, 0.0, 1.0)
+
+		if smooth.GetDifferentiator() != sg {
+			t.Error("Expected same differentiator instance")
+		}
+
+		// An encoder moving at a constant rate produces a quantized, staircase error
+		rate := 37.3
+		var smoothOutputs, rawOutputs []float64
+		for i := range 1000 {
+			state := math.Floor(rate * float64(i) * dt)
+			smoothOutputs = append(smoothOutputs, smooth.CalculateWithDt(0.0, state, dt))
+			rawOutputs = append(rawOutputs, raw.CalculateWithDt(0.0, state, dt))
+		}
+
+		smoothVariance := calculateVariance(smoothOutputs[100:])
+		rawVariance := calculateVariance(rawOutputs[100:])
+		if smoothVariance >= rawVariance/10 {
+			t.Errorf("Expected differentiator variance %f to be far below first difference variance %f",
+				smoothVariance, rawVariance)
+		}
+		var mean float64
+		for _, output := range smoothOutputs[500:] {
+			mean += output / 500
+		}
+		if !almostEqual(mean, -rate, 0.5) {
+			t.Errorf("Expected mean derivative term near %f, got %f", -rate, mean)
+		}
+	})
+
+	t.Run("First difference matches default", func(t *testing.T) {
+		withDifferentiator := New(1.0, 0.5, 0.2, WithDifferentiator(filter.NewFirstDifference()))
+		withoutDifferentiator := New(1.0, 0.5, 0.2)
+		for i := range 50 {
+			state := math.Sin(float64(i) * 0.1)
+			got := withDifferentiator.CalculateWithDt(1.0, state, 0.01)
+			expected := withoutDifferentiator.CalculateWithDt(1.0, state, 0.01)
+			if !almostEqual(got, expected, 1e-12) {
+				t.Fatalf("Step %d: expected %f, got %f", i, expected, got)
+			}
+		}
+	})
+
+	t.Run("Calculate seeds the differentiator", func(t *testing.T) {
+		fd := filter.NewFirstDifference()
+		pid := New(0.0, 0.0, 1.0, WithDifferentiator(fd))
+		pid.Calculate(0.0, 0.0) // Initialize
+		time.Sleep(10 * time.Millisecond)
+		output := pid.Calculate(0.0, 1.0)
+
+		// The first difference sees the initial error, so the step produces a derivative kick
+		if output >= 0 {
+			t.Errorf("Expected negative derivative output after a step, got %f", output)
+		}
+	})
+
+	t.Run("Selectable estimators", func(t *testing.T) {
+		lpf, _ := filter.NewLowPassFilter(0.5)
+		filtered, _ := filter.NewFilteredDerivative(lpf)
+		regression, _ := filter.NewRegressionDerivative(8)
+		tracking, _ := filter.NewTrackingDifferentiator(1000, 0.02)
+
+		for _, d := range []filter.Differentiator{filtered, regression, tracking} {
+			pid := New(0.0, 0.0, 1.0, WithDifferentiator(d))
+			var output float64
+			for i := range 500 {
+				output = pid.CalculateWithDt(0.0, 2.0*float64(i)*0.01, 0.01)
+			}
+			if !almostEqual(output, -2.0, 1e-3) {
+				t.Errorf("%T: expected derivative term -2, got %f", d, output)
+			}
+		}
+	})
+
+	t.Run("Reset and removal", func(t *testing.T) {
+		sg, _ := filter.NewSavitzkyGolayFilter(5, 1, 0.1)
+		pid := New(0.0, 0.0, 1.0, WithDifferentiator(sg))
+		pid.CalculateWithDt(0.0, 1.0, 0.1)
+		pid.CalculateWithDt(0.0, 2.0, 0.1)
+
+		pid.Reset()
+		if sg.GetDerivative() != 0.0 {
+			t.Error("Expected Reset to reset the differentiator")
+		}
+
+		pid.SetDifferentiator(nil)
+		if pid.GetDifferentiator() != nil {
+			t.Error("Expected nil differentiator")
+		}
+	})
+}
+
 func TestReset(t *testing.T) {
 	pid := New(1.0, 1.0, 1.0, WithFilter(newLowPassFilterForTest(0.3)))
 	pid.Calculate(1.0, 0.0) // Initialize